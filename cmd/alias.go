@@ -0,0 +1,173 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+var aliasDelete bool
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias [name [version]]",
+	Short: "Give kubectl versions friendly names",
+	Long: `Give a kubectl version a name that use, exec and which accept in its place:
+
+  kubemngr alias prod 1.21.0
+  kubemngr use prod
+
+Aliases refer to the version rather than the binary, so they keep working
+after the version is reinstalled. Without arguments every alias is listed, with
+only a name the version it refers to is printed.`,
+	Args: cobra.MaximumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if aliasDelete {
+			if len(args) != 1 {
+				fatal("--delete takes the name of one alias")
+			}
+			if err := RemoveAlias(args[0]); err != nil {
+				fatal(err)
+			}
+			return
+		}
+
+		switch len(args) {
+		case 0:
+			if err := printAliases(); err != nil {
+				fatal(err)
+			}
+		case 1:
+			aliases, err := mngr.Aliases()
+			if err != nil {
+				fatal(err)
+			}
+			version, ok := aliases[args[0]]
+			if !ok {
+				fatalf("%v %q", kubemngr.ErrNoAlias, args[0])
+			}
+			fmt.Println(version)
+		default:
+			if err := SetAlias(args[0], args[1]); err != nil {
+				fatal(err)
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+	aliasCmd.Flags().BoolVarP(&aliasDelete, "delete", "d", false, "Remove the alias")
+	aliasCmd.ValidArgsFunction = completeAlias
+}
+
+// SetAlias - makes name refer to version
+func SetAlias(name, version string) error {
+	unlock, err := mngr.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	version = kubemngr.NormalizeVersion(version)
+	if err := mngr.SetAlias(name, version); err != nil {
+		return err
+	}
+
+	if _, err := mngr.Path(version); errors.Is(err, kubemngr.ErrNotInstalled) {
+		warnf("kubectl %s is not installed. Run 'kubemngr install %s'", version, version)
+	}
+
+	fmt.Printf("%s now refers to kubectl %s\n", name, version)
+
+	return nil
+}
+
+// RemoveAlias - removes the alias name, leaving the version it referred to alone
+func RemoveAlias(name string) error {
+	unlock, err := mngr.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	version, err := mngr.RemoveAlias(name)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed alias %s of kubectl %s\n", name, version)
+
+	return nil
+}
+
+// printAliases - lists every alias and its version, sorted by name
+func printAliases() error {
+	aliases, err := mngr.Aliases()
+	if err != nil {
+		return err
+	}
+
+	if len(aliases) == 0 {
+		fmt.Println("No aliases set. See 'kubemngr alias <name> <version>'.")
+		return nil
+	}
+
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(w, "%s\t%s\n", name, aliases[name])
+	}
+
+	return w.Flush()
+}
+
+// warnIfNotInstalled - warns when version, or the version it is an alias of,
+// isn't installed. Pins and the default keep the alias so they follow it.
+func warnIfNotInstalled(version string) {
+	expanded, err := expandAlias(version)
+	if err != nil {
+		warnf("%v", err)
+		return
+	}
+
+	if _, err := mngr.Path(expanded); errors.Is(err, kubemngr.ErrNotInstalled) {
+		warnf("kubectl %s is not installed. Run 'kubemngr install %s'", expanded, expanded)
+	}
+}
+
+// expandAlias - returns the version an alias refers to, or version unchanged
+// when it isn't an alias
+func expandAlias(version string) (string, error) {
+	expanded, err := mngr.ExpandAlias(version)
+	if err != nil {
+		return "", err
+	}
+
+	return kubemngr.NormalizeVersion(expanded), nil
+}
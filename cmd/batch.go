@@ -0,0 +1,42 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+)
+
+// ignoreErrors makes commands run on several versions exit 0 when some failed
+var ignoreErrors bool
+
+// batchSummary counts the outcomes of a command run on several versions
+type batchSummary struct {
+	succeeded, skipped, failed int
+}
+
+// print - prints the counts, naming what succeeding and skipping meant
+func (s *batchSummary) print(succeeded, skipped string) {
+	fmt.Printf("%s: %d, %s: %d, failed: %d\n", succeeded, s.succeeded, skipped, s.skipped, s.failed)
+}
+
+// exit - exits with status 1 when any version failed, unless --ignore-errors was given
+func (s *batchSummary) exit() {
+	if s.failed > 0 && !ignoreErrors {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,165 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+const releaseNotesURL = "https://api.github.com/repos/kubernetes/kubernetes/releases/tags/"
+
+var (
+	usePager bool
+	showEOL  bool
+)
+
+var changelogCmd = &cobra.Command{
+	Use:     "changelog <version>",
+	Aliases: []string{"info"},
+	Short:   "Show the release notes of a Kubernetes version",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if showEOL {
+			if err := printEOL(args[0]); err != nil {
+				fatal(err)
+			}
+			return
+		}
+
+		notes, err := fetchReleaseNotes(args[0])
+		if err != nil {
+			fatal(err)
+		}
+
+		if usePager {
+			err = page(notes)
+		} else {
+			_, err = fmt.Println(notes)
+		}
+		if err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(changelogCmd)
+	changelogCmd.Flags().BoolVar(&usePager, "pager", false, "Show the release notes through $PAGER (default less)")
+	changelogCmd.Flags().BoolVar(&showEOL, "eol", false, "Show when the version's minor release stops getting fixes instead of the release notes")
+	changelogCmd.ValidArgsFunction = completeRemoteVersions
+}
+
+// printEOL - prints the end of life date of version's minor release
+func printEOL(version string) error {
+	version, err := mngr.ResolveRelease(version)
+	if err != nil {
+		return err
+	}
+
+	cycle, err := mngr.EOL(version)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case cycle == nil:
+		fmt.Printf("No end of life data for kubectl %s\n", version)
+	case cycle.EOL.IsZero():
+		fmt.Printf("Kubernetes %s has no end of life date yet\n", cycle.Minor)
+	case cycle.Past(time.Now()):
+		fmt.Printf("Kubernetes %s reached end of life on %s\n", cycle.Minor, cycle.EOL.Format("2006-01-02"))
+	default:
+		fmt.Printf("Kubernetes %s is supported until %s\n", cycle.Minor, cycle.EOL.Format("2006-01-02"))
+	}
+
+	return nil
+}
+
+// fetchReleaseNotes - returns the GitHub release notes for version, from the cache
+// when they have been fetched before
+func fetchReleaseNotes(version string) (string, error) {
+	version, err := mngr.ResolveRelease(version)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	cached := filepath.Join(dir, "changelog", version+".md")
+
+	if notes, err := ioutil.ReadFile(cached); err == nil {
+		return strings.TrimSpace(string(notes)), nil
+	}
+
+	url := releaseNotesURL + version
+	res, err := mngr.HTTPClient().Get(url)
+	if err != nil {
+		return "", requestError("unable to fetch release notes for "+version, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("no release notes found for kubectl %s", version)
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", kubemngr.GitHubResponseError("unable to fetch release notes for "+version, url, res)
+	}
+
+	release := struct {
+		Body string `json:"body"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&release); err != nil {
+		return "", fmt.Errorf("unable to parse release notes for %s: %w", version, err)
+	}
+
+	notes := strings.TrimSpace(release.Body)
+
+	// A failed cache write only means fetching again next time
+	if err := kubemngr.EnsureDir(filepath.Dir(cached)); err == nil {
+		ioutil.WriteFile(cached, []byte(notes), 0644)
+	}
+
+	return notes, nil
+}
+
+// page - writes text through the user's pager
+func page(text string) error {
+	pager := strings.Fields(os.Getenv("PAGER"))
+	if len(pager) == 0 {
+		pager = []string{"less"}
+	}
+
+	cmd := exec.Command(pager[0], pager[1:]...)
+	cmd.Stdin = strings.NewReader(text + "\n")
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
@@ -0,0 +1,100 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+var cleanDryRun bool
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove partial downloads and corrupt kubectl binaries",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		unlock, err := mngr.Lock()
+		if err != nil {
+			fatal(err)
+		}
+		defer unlock()
+
+		if err := CleanBrokenFiles(cleanDryRun); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cleanCmd)
+	cleanCmd.Flags().BoolVar(&cleanDryRun, "dry-run", false, "Show what would be removed without removing anything")
+}
+
+// CleanBrokenFiles - removes leftover partial downloads and kubectl files that
+// aren't valid executables
+func CleanBrokenFiles(dryRun bool) error {
+	broken, err := mngr.Broken()
+	if err != nil {
+		return err
+	}
+
+	return removeFiles(broken, dryRun, "Nothing to clean")
+}
+
+// removeFiles - removes files, or only lists them with dryRun, and reports the space freed
+func removeFiles(files []kubemngr.BrokenFile, dryRun bool, nothing string) error {
+	if len(files) == 0 {
+		fmt.Println(nothing)
+		return nil
+	}
+
+	home, err := mngr.Dir()
+	if err != nil {
+		return err
+	}
+
+	var freed int64
+	for _, file := range files {
+		name, err := filepath.Rel(home, file.Path)
+		if err != nil {
+			name = file.Path
+		}
+
+		if dryRun {
+			fmt.Printf("Would remove %s (%s)\n", name, file.Reason)
+		} else {
+			fmt.Printf("Removing %s (%s)\n", name, file.Reason)
+			if err := os.Remove(file.Path); err != nil {
+				return err
+			}
+		}
+		freed += file.Size
+	}
+
+	if dryRun {
+		fmt.Printf("Would free %s\n", formatBytes(freed))
+	} else {
+		fmt.Printf("Freed %s\n", formatBytes(freed))
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+)
+
+// colorMode is the value of --color: auto, always or never
+var colorMode string
+
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+)
+
+// parseColor - checks the --color value
+func parseColor() error {
+	switch colorMode {
+	case "auto", "always", "never":
+		return nil
+	}
+
+	return fmt.Errorf("invalid --color %q, expected auto, always or never", colorMode)
+}
+
+// colorEnabled - reports whether output written to f should be colored. With
+// auto that is when f is a terminal and NO_COLOR isn't set.
+func colorEnabled(f *os.File) bool {
+	switch colorMode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+
+	return isTerminal(f)
+}
+
+// isTerminal - reports whether f is a terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorize - wraps s in color when output to f should be colored
+func colorize(f *os.File, color, s string) string {
+	if !colorEnabled(f) {
+		return s
+	}
+
+	return color + s + colorReset
+}
+
+// green, yellow and red color text written to stdout
+func green(s string) string  { return colorize(os.Stdout, colorGreen, s) }
+func yellow(s string) string { return colorize(os.Stdout, colorYellow, s) }
+func red(s string) string    { return colorize(os.Stdout, colorRed, s) }
+
+// warning - returns the prefix of a warning written to f
+func warning(f *os.File) string {
+	return colorize(f, colorYellow, "Warning:")
+}
@@ -0,0 +1,86 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hashicorp/go-version"
+	"github.com/spf13/cobra"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <from> <to>",
+	Short: "Compare two installed kubectl versions",
+	Long: `Report how far apart two installed kubectl versions are and whether switching
+from the first to the second is an upgrade or a downgrade.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := CompareKubectlVersions(args[0], args[1]); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(compareCmd)
+	compareCmd.ValidArgsFunction = completeInstalledVersions
+}
+
+// CompareKubectlVersions - prints the difference between two installed versions
+func CompareKubectlVersions(from, to string) error {
+	dir, err := mngr.Dir()
+	if err != nil {
+		return err
+	}
+
+	var versions []*version.Version
+	for _, v := range []string{from, to} {
+		v = kubemngr.NormalizeVersion(v)
+		if _, err := os.Stat(kubemngr.BinaryPath(dir, v)); os.IsNotExist(err) {
+			return fmt.Errorf("kubectl %s is not installed", v)
+		}
+
+		parsed, err := version.NewVersion(v)
+		if err != nil {
+			return fmt.Errorf("unable to compare %s: %w", v, err)
+		}
+		versions = append(versions, parsed)
+	}
+
+	a, b := versions[0], versions[1]
+
+	var direction string
+	switch a.Compare(b) {
+	case 0:
+		fmt.Printf("%s and %s are the same version\n", a.Original(), b.Original())
+		return nil
+	case -1:
+		direction = "upgrade"
+	default:
+		direction = "downgrade"
+	}
+
+	// Segments are always padded to major, minor and patch
+	sa, sb := a.Segments(), b.Segments()
+	fmt.Printf("%s -> %s: %s (major %+d, minor %+d, patch %+d)\n",
+		a.Original(), b.Original(), direction, sb[0]-sa[0], sb[1]-sa[1], sb[2]-sa[2])
+
+	return nil
+}
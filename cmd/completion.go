@@ -0,0 +1,208 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Generate shell completion scripts",
+	Long: `Generate a completion script for kubemngr. Besides commands and flags, it completes
+installed versions for use, uninstall, which, exec and verify, and available
+versions for install. Neither touches the network: installed versions are read
+from the kubemngr directory, and install suggests latest, stable and the newest
+versions in the cached list that update-index refreshes.
+
+Bash:
+  $ source <(kubemngr completion bash)
+
+  # To load completions for each session, execute once:
+  $ kubemngr completion bash > /etc/bash_completion.d/kubemngr
+
+Zsh:
+  # If shell completion is not already enabled in your environment,
+  # enable it by executing once:
+  $ echo "autoload -U compinit; compinit" >> ~/.zshrc
+
+  # To load completions for each session, execute once:
+  $ kubemngr completion zsh > "${fpath[1]}/_kubemngr"
+
+Fish:
+  $ kubemngr completion fish | source
+
+  # To load completions for each session, execute once:
+  $ kubemngr completion fish > ~/.config/fish/completions/kubemngr.fish
+
+PowerShell:
+  PS> kubemngr completion powershell | Out-String | Invoke-Expression
+`,
+	ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+	Args:      cobra.ExactValidArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+		switch args[0] {
+		case "bash":
+			err = rootCmd.GenBashCompletion(os.Stdout)
+		case "zsh":
+			err = rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			err = rootCmd.GenFishCompletion(os.Stdout, true)
+		case "powershell":
+			err = rootCmd.GenPowerShellCompletion(os.Stdout)
+		}
+
+		if err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+
+	installCmd.ValidArgsFunction = completeRemoteVersions
+	downloadCmd.ValidArgsFunction = completeRemoteVersions
+	useCmd.ValidArgsFunction = completeVersionOrAlias
+	uninstallCmd.ValidArgsFunction = completeEachInstalledVersion
+	verifyCmd.ValidArgsFunction = completeEachInstalledVersion
+}
+
+// completeInstalledVersions - suggests the versions installed locally for the first argument
+func completeInstalledVersions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return installedSuggestions(nil, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeVersionOrAlias - suggests the versions installed locally and the
+// aliases, for commands that accept either
+func completeVersionOrAlias(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return append(installedSuggestions(nil, toComplete), aliasSuggestions(toComplete)...), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeAlias - suggests the aliases for the name, then the installed
+// versions for the version it should refer to
+func completeAlias(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return aliasSuggestions(toComplete), cobra.ShellCompDirectiveNoFileComp
+	case 1:
+		return installedSuggestions(nil, toComplete), cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
+
+// aliasSuggestions - lists the aliases starting with toComplete, sorted
+func aliasSuggestions(toComplete string) []string {
+	aliases, err := mngr.Aliases()
+	if err != nil {
+		return nil
+	}
+
+	var suggestions []string
+	for name := range aliases {
+		if strings.HasPrefix(name, toComplete) {
+			suggestions = append(suggestions, name)
+		}
+	}
+	sort.Strings(suggestions)
+
+	return suggestions
+}
+
+// completeEachInstalledVersion - suggests the installed versions not given
+// yet, for commands that take several
+func completeEachInstalledVersion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return installedSuggestions(args, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// installedSuggestions - lists the installed versions of --binary starting
+// with toComplete, leaving out those in given. Completion mustn't print
+// errors, so a missing or unreadable directory just suggests nothing.
+func installedSuggestions(given []string, toComplete string) []string {
+	installed, err := mngr.ListBinary(binary)
+	if err != nil {
+		return nil
+	}
+
+	skip := map[string]bool{}
+	for _, v := range given {
+		skip[kubemngr.NormalizeVersion(v)] = true
+	}
+
+	var suggestions []string
+	for _, v := range installed {
+		version := v.Version.Original()
+		if strings.HasPrefix(version, toComplete) && !skip[version] {
+			suggestions = append(suggestions, version)
+		}
+	}
+
+	return suggestions
+}
+
+// remoteCompletionLimit caps how many versions install completion suggests
+const remoteCompletionLimit = 20
+
+// completeRemoteVersions - suggests the latest and stable keywords and the
+// newest versions available to install. Only the cached version list is read,
+// so completion never waits on the network and offers just the keywords until
+// list-remote or update-index has cached one.
+func completeRemoteVersions(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var suggestions []string
+	for _, keyword := range []string{"latest", "stable"} {
+		if strings.HasPrefix(keyword, toComplete) {
+			suggestions = append(suggestions, keyword)
+		}
+	}
+
+	versions, err := mngr.CachedRemoteVersions()
+	if err != nil {
+		return suggestions, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	count := 0
+	for _, v := range versions {
+		if count == remoteCompletionLimit {
+			break
+		}
+		if strings.HasPrefix(v.Original(), toComplete) {
+			suggestions = append(suggestions, v.Original())
+			count++
+		}
+	}
+
+	return suggestions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,224 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+// configKeys are the settings that can be stored in the config file, with a
+// parser that validates and converts values given to 'config set'
+var configKeys = map[string]func(string) (interface{}, error){
+	"binary_mode":   parseMode,
+	"cacert":        parseString,
+	"default":       parseVersion,
+	"dir_mode":      parseMode,
+	"index_ttl":     parseDuration,
+	"mirror":        parseString,
+	"offline":       parseBool,
+	"shims":         parseBool,
+	"signature_key": parseString,
+	"timeout":       parseDuration,
+	"retries":       parseInt,
+	"keep":          parseInt,
+	"skip_checksum": parseBool,
+	"update_check":  parseBool,
+}
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and change kubemngr settings",
+	Long: `Inspect and change the settings stored in the kubemngr config file, by default
+$XDG_CONFIG_HOME/kubemngr/config.yaml. Settings are taken from flags first, then
+KUBEMNGR_* environment variables, then the config file, then built-in defaults.`,
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the effective value of a setting",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, ok := configKeys[args[0]]; !ok {
+			fatalf("unknown setting %q", args[0])
+		}
+
+		fmt.Println(viper.Get(args[0]))
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Store a setting in the config file",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := SetConfigValue(args[0], args[1]); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print the effective value of every setting",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		keys := make([]string, 0, len(configKeys))
+		for key := range configKeys {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			fmt.Printf("%s = %v\n", key, viper.Get(key))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd, configSetCmd, configListCmd)
+}
+
+// SetConfigValue - validates value and writes it to the config file under key
+func SetConfigValue(key, value string) error {
+	parsed, path, err := writeConfigValue(key, value)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Set %s to %v in %s\n", key, parsed, path)
+
+	return nil
+}
+
+// writeConfigValue - stores value under key in the config file, returning the
+// converted value and the file's path
+func writeConfigValue(key, value string) (interface{}, string, error) {
+	parse, ok := configKeys[key]
+	if !ok {
+		return nil, "", fmt.Errorf("unknown setting %q", key)
+	}
+
+	parsed, err := parse(value)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+
+	path := viper.ConfigFileUsed()
+	if path == "" {
+		if path, err = configPath(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	// Only touch what is in the file, not values that came from flags or the environment
+	file := viper.New()
+	file.SetConfigFile(path)
+	if err := file.ReadInConfig(); err != nil && !os.IsNotExist(err) {
+		return nil, "", fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	file.Set(key, parsed)
+
+	if err := kubemngr.EnsureDir(filepath.Dir(path)); err != nil {
+		return nil, "", err
+	}
+	if err := file.WriteConfigAs(path); err != nil {
+		return nil, "", fmt.Errorf("unable to write %s: %w", path, err)
+	}
+
+	return parsed, path, nil
+}
+
+// applyConfig - copies the effective settings into the variables commands read
+func applyConfig() {
+	timeout = viper.GetDuration("timeout")
+	retries = viper.GetInt("retries")
+	pruneKeep = viper.GetInt("keep")
+	skipChecksum = viper.GetBool("skip_checksum")
+}
+
+func parseString(value string) (interface{}, error) {
+	return value, nil
+}
+
+// parseVersion - accepts a release version or an existing alias
+func parseVersion(value string) (interface{}, error) {
+	if kubemngr.ValidateAlias(value) == nil {
+		aliases, err := mngr.Aliases()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := aliases[value]; !ok {
+			return nil, fmt.Errorf("%w %q", kubemngr.ErrNoAlias, value)
+		}
+		return value, nil
+	}
+
+	version := kubemngr.NormalizeVersion(value)
+	if err := kubemngr.ValidateVersion(version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+func parseDuration(value string) (interface{}, error) {
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return nil, err
+	}
+	return d.String(), nil
+}
+
+func parseInt(value string) (interface{}, error) {
+	return strconv.Atoi(value)
+}
+
+// parseMode - accepts an octal permission such as 0700, stored as a string so
+// the config file stays readable
+func parseMode(value string) (interface{}, error) {
+	mode, err := fileMode(value)
+	if err != nil {
+		return nil, err
+	}
+	return fmt.Sprintf("%#o", mode), nil
+}
+
+// fileMode - parses an octal permission, 0 when value is empty
+func fileMode(value string) (os.FileMode, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	mode, err := strconv.ParseUint(value, 8, 32)
+	if err != nil || mode == 0 || mode > 0777 {
+		return 0, fmt.Errorf("invalid mode %q, expected octal permissions such as 0755", value)
+	}
+	return os.FileMode(mode), nil
+}
+
+func parseBool(value string) (interface{}, error) {
+	return strconv.ParseBool(value)
+}
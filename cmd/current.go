@@ -0,0 +1,104 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+var currentPath bool
+
+var currentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Show the kubectl version currently in use",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		version, path, err := currentKubectl()
+		if err != nil {
+			fatal(err)
+		}
+
+		if currentPath {
+			fmt.Println(path)
+		} else {
+			fmt.Println(version)
+		}
+
+		if !quiet {
+			warnIfShadowed()
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(currentCmd)
+	currentCmd.Flags().BoolVar(&currentPath, "path", false, "Print the path of the active kubectl binary instead of its version")
+}
+
+// currentKubectl - returns the kubectl version in effect for the working directory
+// and the path of its binary. A version file takes precedence over the default
+// version, which takes precedence over the version use linked, so the default
+// stays a stable baseline even if the link is changed by hand.
+func currentKubectl() (string, string, error) {
+	version, file, err := pinnedVersion()
+	if err != nil {
+		return "", "", err
+	}
+	if version != "" {
+		return installedKubectl(version, "pinned by "+file)
+	}
+
+	if version := defaultVersion(); version != "" {
+		return installedKubectl(version, "the default")
+	}
+
+	version, err = mngr.Active()
+	if err != nil {
+		return "", "", errors.New("no kubectl version is in use. See 'kubemngr use <version>'")
+	}
+
+	path, err := mngr.Path(version)
+	if err != nil {
+		return "", "", fmt.Errorf("kubectl %s is in use but its binary is missing. Run 'kubemngr install %s'", version, version)
+	}
+
+	return version, path, nil
+}
+
+// installedKubectl - returns version, or the version it is an alias of, and
+// the path of its binary, or an error saying where version came from when it
+// isn't installed
+func installedKubectl(version, source string) (string, string, error) {
+	version, err := expandAlias(version)
+	if err != nil {
+		return "", "", err
+	}
+
+	path, err := mngr.Path(version)
+	if errors.Is(err, kubemngr.ErrNotInstalled) {
+		return "", "", fmt.Errorf("kubectl %s is %s but not installed. Run 'kubemngr install %s'", version, source, version)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	return version, path, nil
+}
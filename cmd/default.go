@@ -0,0 +1,74 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+var defaultCmd = &cobra.Command{
+	Use:   "default [version]",
+	Short: "Set or show the default kubectl version",
+	Long: `Set the kubectl version that current and exec fall back to when no
+.kubemngr-version file or .tool-versions kubectl line is found. Without a version,
+print the current default.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			version := defaultVersion()
+			if version == "" {
+				fmt.Fprintln(os.Stderr, "no default kubectl version is set")
+				os.Exit(1)
+			}
+			fmt.Println(version)
+			return
+		}
+
+		if err := SetDefaultVersion(args[0]); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(defaultCmd)
+	defaultCmd.ValidArgsFunction = completeInstalledVersions
+}
+
+// SetDefaultVersion - stores version as the default in the config file
+func SetDefaultVersion(version string) error {
+	version = kubemngr.NormalizeVersion(version)
+	if _, _, err := writeConfigValue("default", version); err != nil {
+		return err
+	}
+
+	warnIfNotInstalled(version)
+
+	fmt.Printf("Default kubectl version is now %s\n", version)
+
+	return nil
+}
+
+// defaultVersion - returns the default version from the config, or an empty string
+func defaultVersion() string {
+	return kubemngr.NormalizeVersion(viper.GetString("default"))
+}
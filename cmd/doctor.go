@@ -0,0 +1,174 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	checkOK   = "OK"
+	checkWarn = "WARN"
+	checkFail = "FAIL"
+)
+
+// checkResult is the outcome of a single doctor check, with a hint on how to fix anything but OK
+type checkResult struct {
+	status string
+	detail string
+	hint   string
+}
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check for common problems with the kubemngr setup",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		checks := []struct {
+			name string
+			run  func() checkResult
+		}{
+			{"kubemngr directory", checkKubemngrDir},
+			{"active kubectl", checkActiveLink},
+			{"binary names", checkBinaryNames},
+			{"PATH", checkBinDirOnPath},
+			{"PATH order", checkShadowing},
+			{"release bucket", checkReachability},
+		}
+
+		failed := false
+		for _, check := range checks {
+			result := check.run()
+			fmt.Printf("[%s] %s: %s\n", statusLabel(result.status), check.name, result.detail)
+			if result.status != checkOK && result.hint != "" {
+				fmt.Printf("       %s\n", result.hint)
+			}
+			failed = failed || result.status == checkFail
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+// statusLabel - pads and colors a check status
+func statusLabel(status string) string {
+	label := fmt.Sprintf("%-4s", status)
+	switch status {
+	case checkOK:
+		return green(label)
+	case checkWarn:
+		return yellow(label)
+	}
+
+	return red(label)
+}
+
+func checkKubemngrDir() checkResult {
+	dir, err := mngr.Dir()
+	if err != nil {
+		return checkResult{checkFail, err.Error(), "Remove or rename whatever is in the way, or set KUBEMNGR_HOME"}
+	}
+
+	probe, err := ioutil.TempFile(dir, ".doctor")
+	if err != nil {
+		return checkResult{checkFail, dir + " is not writable", "Fix the permissions of " + dir}
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	return checkResult{checkOK, dir, ""}
+}
+
+func checkActiveLink() checkResult {
+	if mngr.HasShim() {
+		return checkResult{checkOK, mngr.ShimPath() + " is a shim, the version follows the working directory", ""}
+	}
+
+	link := mngr.LinkPath()
+	if _, err := os.Lstat(link); os.IsNotExist(err) {
+		return checkResult{checkWarn, "no kubectl version is in use", "Run 'kubemngr use <version>'"}
+	}
+
+	target, err := filepath.EvalSymlinks(link)
+	if err != nil {
+		return checkResult{checkFail, link + " points to a missing binary", "Run 'kubemngr repair', or 'kubemngr use <version>' to switch to an installed version"}
+	}
+
+	return checkResult{checkOK, target, ""}
+}
+
+func checkBinaryNames() checkResult {
+	renames, err := mngr.MigrateNames(true)
+	if err != nil {
+		return checkResult{checkFail, err.Error(), ""}
+	}
+
+	if len(renames) > 0 {
+		return checkResult{checkWarn, fmt.Sprintf("%d binaries are named the way older releases did", len(renames)), "Run 'kubemngr gc' to rename them"}
+	}
+
+	return checkResult{checkOK, "all binaries use the current naming", ""}
+}
+
+func checkBinDirOnPath() checkResult {
+	binDir := filepath.Dir(mngr.LinkPath())
+	if onPath(binDir) {
+		return checkResult{checkOK, binDir + " is on PATH", ""}
+	}
+
+	return checkResult{checkFail, binDir + " is not on PATH", `Add it to your shell profile with eval "$(kubemngr env)"`}
+}
+
+func checkShadowing() checkResult {
+	shadow, err := shadowingKubectl()
+	if err != nil {
+		return checkResult{checkFail, err.Error(), ""}
+	}
+
+	if shadow != "" {
+		link := mngr.LinkPath()
+		return checkResult{checkWarn, shadow + " is found before the managed kubectl", "Move " + filepath.Dir(link) + " ahead of " + filepath.Dir(shadow) + " in PATH"}
+	}
+
+	return checkResult{checkOK, "the managed kubectl comes first", ""}
+}
+
+func checkReachability() checkResult {
+	base, err := mngr.BaseURL()
+	if err != nil {
+		return checkResult{checkFail, err.Error(), "Fix the mirror setting with 'kubemngr config set mirror <url>'"}
+	}
+
+	res, err := mngr.HTTPClient().Head(base)
+	if err != nil {
+		return checkResult{checkFail, requestError("unable to reach "+base, err).Error(), "Check your network connection and proxy settings"}
+	}
+	res.Body.Close()
+
+	return checkResult{checkOK, base + " is reachable", ""}
+}
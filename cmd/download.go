@@ -0,0 +1,33 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var downloadCmd = &cobra.Command{
+	Use:   "download <version>...",
+	Short: "Download kubectl versions without switching to them",
+	Long: `Download and verify kubectl versions exactly like install, without ever
+changing the kubectl in use. Useful for warming a cache of versions in CI.`,
+	Run: runInstall,
+}
+
+func init() {
+	rootCmd.AddCommand(downloadCmd)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var envShell string
+
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Print the shell commands that put kubectl on PATH",
+	Long: `Print the commands that add the directory holding the kubectl symlink to PATH,
+so it can be evaluated from your shell's rc file:
+
+  eval "$(kubemngr env)"
+
+The syntax follows $SHELL unless --shell is given. Evaluating it more than once
+doesn't add the directory again.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		shell := envShell
+		if shell == "" {
+			shell = detectShell()
+		}
+
+		script, err := pathScript(shell, filepath.Dir(mngr.LinkPath()))
+		if err != nil {
+			fatal(err)
+		}
+		fmt.Print(script)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+	envCmd.Flags().StringVar(&envShell, "shell", "", "Shell to print commands for: bash, zsh, fish or powershell")
+}
+
+// detectShell - guesses the shell from $SHELL, which Windows doesn't set
+func detectShell() string {
+	if shell := filepath.Base(os.Getenv("SHELL")); shell != "." && shell != string(filepath.Separator) {
+		return shell
+	}
+	if runtime.GOOS == "windows" {
+		return "powershell"
+	}
+
+	return "sh"
+}
+
+// pathScript - returns the commands prepending dir to PATH in shell's syntax
+// unless it is already there
+func pathScript(shell, dir string) (string, error) {
+	switch shell {
+	case "sh", "bash", "zsh", "ksh", "dash":
+		return fmt.Sprintf(`case ":$PATH:" in
+  *":%[1]s:"*) ;;
+  *) export PATH="%[1]s:$PATH" ;;
+esac
+`, dir), nil
+	case "fish":
+		return fmt.Sprintf("contains -- %[1]q $PATH; or set -gx PATH %[1]q $PATH\n", dir), nil
+	case "powershell", "pwsh":
+		quoted := "'" + strings.Replace(dir, "'", "''", -1) + "'"
+		return fmt.Sprintf("if (-not (($env:PATH -split [IO.Path]::PathSeparator) -contains %[1]s)) { $env:PATH = %[1]s + [IO.Path]::PathSeparator + $env:PATH }\n", quoted), nil
+	}
+
+	return "", fmt.Errorf("unsupported shell %q, expected bash, zsh, fish or powershell", shell)
+}
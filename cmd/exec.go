@@ -0,0 +1,81 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+var execCmd = &cobra.Command{
+	Use:   "exec [version] -- [kubectl args...]",
+	Short: "Run a specific kubectl version without switching to it",
+	Long: `Run kubectl with the given arguments using a specific installed version, for example:
+
+  kubemngr exec v1.21.0 -- get pods
+
+Without a version, the pinned, default or active version is used.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var version string
+		kubectlArgs := args
+
+		switch dash := cmd.ArgsLenAtDash(); {
+		case dash == 1, dash < 0 && len(args) > 0:
+			version, kubectlArgs = args[0], args[1:]
+		case dash > 1:
+			fatal("only a version may be given before --")
+		}
+
+		if err := ExecKubectl(version, kubectlArgs); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+	execCmd.ValidArgsFunction = completeVersionOrAlias
+}
+
+// ExecKubectl - runs version of kubectl with args, or the current version when version is empty
+func ExecKubectl(version string, args []string) error {
+	var path string
+	if version == "" {
+		var err error
+		if _, path, err = currentKubectl(); err != nil {
+			return err
+		}
+	} else {
+		dir, err := mngr.Dir()
+		if err != nil {
+			return err
+		}
+
+		if version, err = expandAlias(version); err != nil {
+			return err
+		}
+		path = kubemngr.BinaryPath(dir, version)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return fmt.Errorf("kubectl %s is not installed. Run 'kubemngr install %s' first", version, version)
+		}
+	}
+
+	return kubemngr.Exec(path, args)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+var exportChecksums bool
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Print the installed kubectl versions for import on another machine",
+	Long: `Print the installed kubectl versions, one per line, in the format import reads:
+
+  kubemngr export > versions.txt
+  kubemngr import versions.txt
+
+With --checksums each line also has the platform, SHA256 checksum and source of
+the binary, and import warns when a binary it installs for the same platform
+doesn't match.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		installed, err := mngr.List()
+		if err != nil {
+			fatal(err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, v := range installed {
+			if !exportChecksums {
+				fmt.Fprintln(w, v.Version.Original())
+				continue
+			}
+
+			sum, source := v.SHA256, v.Source
+			if sum == "" {
+				sum = "-"
+			}
+			if source == "" {
+				source = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s/%s\t%s\t%s\n", v.Version.Original(), v.OS, v.Arch, sum, source)
+		}
+		w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().BoolVar(&exportChecksums, "checksums", false, "Include the platform, checksum and source of each binary")
+}
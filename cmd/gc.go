@@ -0,0 +1,96 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+var gcDryRun bool
+
+var gcCmd = &cobra.Command{
+	Use:   "gc",
+	Short: "Remove files in the kubemngr directory that nothing refers to",
+	Long: `Remove files in the kubemngr directory that aren't installed kubectl binaries or
+listed in the manifest: temporary files, symlinks to removed binaries and files
+that aren't kubectl binaries. Binaries named the way older releases did, such as
+kubectl-1.21.0 or a build for this machine with an os-arch suffix, are renamed
+first rather than removed. Directories are left alone. Run with --dry-run first
+to see what would be changed.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		unlock, err := mngr.Lock()
+		if err != nil {
+			fatal(err)
+		}
+		defer unlock()
+
+		renamed, err := renameBinaries(gcDryRun)
+		if err != nil {
+			fatal(err)
+		}
+
+		garbage, err := mngr.Garbage()
+		if err != nil {
+			fatal(err)
+		}
+
+		// A dry run leaves the old names in place, they aren't garbage
+		var collect []kubemngr.BrokenFile
+		for _, file := range garbage {
+			if !renamed[file.Path] {
+				collect = append(collect, file)
+			}
+		}
+		garbage = collect
+
+		if err := removeFiles(garbage, gcDryRun, "Nothing to collect"); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(gcCmd)
+	gcCmd.Flags().BoolVar(&gcDryRun, "dry-run", false, "Show what would be removed without removing anything")
+}
+
+// renameBinaries - moves binaries stored under old names to the current ones,
+// or only lists them with dryRun, and returns the old paths
+func renameBinaries(dryRun bool) (map[string]bool, error) {
+	renames, err := mngr.MigrateNames(dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	renamed := map[string]bool{}
+	for _, rename := range renames {
+		from, to := filepath.Base(rename.From), filepath.Base(rename.To)
+		if dryRun {
+			fmt.Printf("Would rename %s to %s\n", from, to)
+		} else {
+			fmt.Printf("Renamed %s to %s\n", from, to)
+		}
+		renamed[rename.From] = true
+	}
+
+	return renamed, nil
+}
@@ -0,0 +1,99 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+var (
+	proxy    string
+	proxyURL *url.URL
+
+	// rootCAs holds the system CAs plus the --cacert bundle, nil without one
+	rootCAs *x509.CertPool
+
+	insecureSkipTLSVerify bool
+
+	// githubToken authenticates GitHub API requests, GITHUB_TOKEN when not given
+	githubToken string
+
+	// timeout applies to metadata requests such as version lists and checksums
+	timeout time.Duration
+)
+
+// parseProxy - validates the --proxy flag
+func parseProxy() error {
+	if proxy == "" {
+		return nil
+	}
+
+	u, err := url.Parse(proxy)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid proxy %q, expected a URL such as http://proxy.example.com:3128", proxy)
+	}
+
+	proxyURL = u
+	return nil
+}
+
+// parseTLS - loads the --cacert bundle and warns when TLS verification is off
+func parseTLS() error {
+	if insecureSkipTLSVerify {
+		warnf("TLS certificate verification is disabled, anyone between you and the server can read and change what kubemngr downloads")
+	}
+
+	cacert := viper.GetString("cacert")
+	if cacert == "" {
+		return nil
+	}
+
+	pem, err := ioutil.ReadFile(cacert)
+	if err != nil {
+		return fmt.Errorf("unable to read --cacert: %w", err)
+	}
+
+	// Trust the bundle on top of the system CAs so other hosts keep working
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(pem) {
+		return fmt.Errorf("no PEM certificates found in %s", cacert)
+	}
+
+	rootCAs = pool
+	return nil
+}
+
+// requestError - wraps a failed request, calling out timeouts explicitly
+func requestError(action string, err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("%s: timed out after %s, see --timeout", action, timeout)
+	}
+
+	return fmt.Errorf("%s: %w", action, err)
+}
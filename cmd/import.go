@@ -0,0 +1,141 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+// importEntry is a line of an export file
+type importEntry struct {
+	version  string
+	platform string
+	sha256   string
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Install the kubectl versions listed by export",
+	Long: `Install each kubectl version listed in a file written by export, or read from
+stdin when the file is - or left out. Versions already installed are skipped and
+a summary is printed at the end. Blank lines and lines starting with # are ignored.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		in := io.Reader(os.Stdin)
+		if len(args) == 1 && args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				fatal(err)
+			}
+			defer f.Close()
+			in = f
+		}
+
+		entries, err := readImport(in)
+		if err != nil {
+			fatal(err)
+		}
+		if len(entries) == 0 {
+			fmt.Println("No versions to import")
+			return
+		}
+
+		unlock, err := mngr.Lock()
+		if err != nil {
+			fatal(err)
+		}
+		defer unlock()
+
+		// A file may list a version twice, installing it once is enough
+		seen := map[string]bool{}
+		versions := make([]string, 0, len(entries))
+		for _, entry := range entries {
+			version := kubemngr.NormalizeVersion(entry.version)
+			if !seen[version] {
+				seen[version] = true
+				versions = append(versions, version)
+			}
+		}
+
+		summary := installAll(cmd.Context(), versions, parallel, false)
+		checkImported(entries)
+		rehashIfEnabled()
+		summary.exit()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().BoolVar(&ignoreErrors, "ignore-errors", false, "Exit 0 even if some versions failed to install")
+}
+
+// readImport - parses an export file: a version per line, optionally followed
+// by the platform, checksum and source
+func readImport(r io.Reader) ([]importEntry, error) {
+	var entries []importEntry
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		entry := importEntry{version: fields[0]}
+		if len(fields) >= 3 {
+			entry.platform, entry.sha256 = fields[1], fields[2]
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// checkImported - warns about installed binaries whose checksum differs from
+// the one exported for the same platform
+func checkImported(entries []importEntry) {
+	installed, err := mngr.List()
+	if err != nil {
+		return
+	}
+
+	sums := map[string]string{}
+	for _, v := range installed {
+		sums[v.Version.Original()] = v.SHA256
+	}
+
+	host := runtime.GOOS + "/" + runtime.GOARCH
+	for _, entry := range entries {
+		if entry.platform != host || entry.sha256 == "-" || entry.sha256 == "" {
+			continue
+		}
+
+		version := kubemngr.NormalizeVersion(entry.version)
+		if sum, ok := sums[version]; ok && sum != "" && sum != entry.sha256 {
+			warnf("kubectl %s doesn't match the exported checksum: %s, expected %s", version, sum, entry.sha256)
+		}
+	}
+}
@@ -17,133 +17,311 @@ limitations under the License.
 package cmd
 
 import (
-	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"log"
-	"os"
-	"strings"
+	"runtime"
+	"sync"
+	"time"
 
-	"github.com/gabriel-vasile/mimetype"
-	getter "github.com/hashicorp/go-getter"
 	"github.com/spf13/cobra"
-	"golang.org/x/sys/unix"
+	"github.com/spf13/viper"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+var (
+	parallel       int
+	skipChecksum   bool
+	checkSignature bool
+	rosetta        bool
+	retries        int
+	installForce   bool
+	installFrom    string
+	verifyExec     bool
+	verifyExecSet  bool
+	installOS      string
+	installArch    string
+	installDryRun  bool
+
+	downloadTimeout time.Duration
+
+	// binary is the release binary install, list, use, which and uninstall work on
+	binary string
+
+	// withConvert installs the kubectl-convert plugin along with kubectl
+	withConvert bool
 )
 
 // installCmd represents the install command
 var installCmd = &cobra.Command{
-	Use:   "install",
-	Short: "A tool manage different kubectl versions inside a workspace.",
-	Run: func(cmd *cobra.Command, args []string) {
-		if len(args) > 0 {
-			err := DownloadKubectl(args[0])
-
-			if err != nil {
-				log.Fatal(err)
-			}
-
-		} else {
-			fmt.Println("specify a kubectl version to install")
-		}
-	},
+	Use:     "install",
+	Aliases: []string{"reinstall"},
+	Short:   "A tool manage different kubectl versions inside a workspace.",
+	Run:     runInstall,
 }
 
 func init() {
 	rootCmd.AddCommand(installCmd)
+	installCmd.Flags().BoolVar(&installForce, "force", false, "Replace the version if it is already installed")
+	installCmd.Flags().BoolVar(&installDryRun, "dry-run", false, "Print the URL and path the version would be installed from and to without downloading it")
+	installCmd.Flags().StringVar(&binary, "binary", kubemngr.DefaultBinary, "Release binary to install: kubectl, kubeadm, kubelet or kubectl-convert")
+	installCmd.Flags().BoolVar(&withConvert, "with-convert", false, "Also install the kubectl-convert plugin of the same version, for kubectl convert")
+	installCmd.Flags().StringVar(&installFrom, "from", "", "Install a kubectl binary already on disk instead of downloading it")
+	installCmd.Flags().BoolVar(&ignoreErrors, "ignore-errors", false, "Exit 0 even if some of several versions failed to install")
+	installCmd.Flags().IntVar(&parallel, "parallel", 1, "Number of versions to download at once when installing several")
+	installCmd.Flags().BoolVar(&skipChecksum, "skip-checksum", false, "Skip verifying the SHA256 checksum of the download")
+	viper.BindPFlag("skip_checksum", installCmd.Flags().Lookup("skip-checksum"))
+	installCmd.Flags().DurationVar(&downloadTimeout, "download-timeout", 10*time.Minute, "Timeout for downloading the kubectl binary")
+	installCmd.Flags().IntVar(&retries, "retries", 3, "Number of download attempts before giving up")
+	viper.BindPFlag("retries", installCmd.Flags().Lookup("retries"))
+	installCmd.Flags().String("mirror", "", "Base URL to download kubectl from, or a preset: default, gcs, cn")
+	viper.BindPFlag("mirror", installCmd.Flags().Lookup("mirror"))
+	installCmd.Flags().BoolVar(&checkSignature, "verify-signature", false, "Verify the cosign signature of the download")
+	installCmd.Flags().String("signature-key", "", "PEM public key or CA certificate trusted to sign kubectl releases")
+	viper.BindPFlag("signature_key", installCmd.Flags().Lookup("signature-key"))
+	installCmd.Flags().BoolVar(&pre, "pre", false, "Allow latest and version ranges to resolve to alpha, beta and rc versions")
+	installCmd.Flags().BoolVar(&verifyExec, "verify-exec", true, "Run the downloaded kubectl to check it works, on by default only for this machine's OS and arch")
+	installCmd.Flags().StringVar(&installOS, "os", "", "Download the build for another OS: darwin, linux or windows")
+	installCmd.Flags().StringVar(&installArch, "arch", "", "Download the build for another arch, such as amd64 or arm64")
+	installCmd.Flags().StringVar(&progressMode, "progress", progressBar, "How to show download progress: bar, plain (a line every few seconds) or json (a JSON object per line)")
+	installCmd.Flags().BoolVar(&noProgress, "no-progress", false, "Don't show download progress, the default for the bar when stderr isn't a terminal")
+	installCmd.Flags().BoolVar(&rosetta, "rosetta", false, "Install the darwin/amd64 build when a version has no darwin/arm64 build")
+
+	// download takes the same flags, sharing them keeps the config bindings working for both
+	downloadCmd.Flags().AddFlagSet(installCmd.Flags())
 }
 
-//DownloadKubectl - download user specified version of kubectl
-func DownloadKubectl(version string) error {
+// runInstall - installs the versions in args, shared by install and download
+func runInstall(cmd *cobra.Command, args []string) {
+	if cmd.CalledAs() == "reinstall" {
+		installForce = true
+	}
+	verifyExecSet = cmd.Flags().Changed("verify-exec")
+	if _, err := progressTracker(); err != nil {
+		fatal(err)
+	}
 
-	// TODO use tmp directory to download instead of kubemngr.
-	// This was failing originally with the error: invalid cross-link device
-	// filepath := "/tmp/"
+	// download never changes what is in use, install keeps kubectl-convert linked
+	link := cmd.Name() == "install"
 
-	// TODO better sanity check for checking arg is valid
-	if len(version) == 0 {
-		log.Fatal(0)
+	// A dry run doesn't write anything so it doesn't need to wait for other operations
+	if !installDryRun {
+		unlock, err := mngr.Lock()
+		if err != nil {
+			fatal(err)
+		}
+		defer unlock()
+		defer rehashIfEnabled()
 	}
 
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		log.Fatal(err)
+	switch {
+	case len(args) == 1:
+		err := DownloadKubectl(cmd.Context(), args[0], link)
+
+		if errors.Is(err, kubemngr.ErrAlreadyInstalled) {
+			fmt.Printf("%v. Pass --force to reinstall it\n", err)
+		} else if err != nil {
+			fatal(err)
+		}
+
+	case len(args) > 1 && installFrom != "":
+		fatal("--from installs a single version")
+
+	case len(args) > 1:
+		summary := installAll(cmd.Context(), args, parallel, link)
+		summary.exit()
+
+	default:
+		fmt.Println("specify a kubectl version to install")
 	}
-	kubectl := fmt.Sprintf("%v/.kubemngr/kubectl-%v", homeDir, version)
+}
 
-	// Check if current version already exists
-	if _, err = os.Stat(kubectl); err == nil {
-		fmt.Printf("%s is already installed.", version)
-		os.Exit(0)
+//DownloadKubectl - download user specified version of kubectl. Cancelling ctx stops the download.
+// With link the kubectl-convert plugin installed by --with-convert is linked if its kubectl is in use.
+func DownloadKubectl(ctx context.Context, version string, link bool) error {
+	if !withConvert || binary != kubemngr.DefaultBinary {
+		return installBinary(ctx, binary, version)
 	}
 
-	// Create temp file of kubectl version in tmp directory
-	out, err := os.Create(kubectl)
-	if err != nil {
-		log.Fatal(err)
+	switch {
+	case installFrom != "":
+		return errors.New("--with-convert can't be combined with --from")
+	case installOS != "" || installArch != "":
+		return errors.New("--with-convert only installs kubectl-convert for this machine")
 	}
-	defer out.Close()
 
-	uname := getOSInfo()
-	// Compare system name to set value for building url to download kubectl binary
-	if uname.Sysname != "Linux" && uname.Sysname != "Darwin" {
-		fmt.Printf("Unsupported OS: %s\nCheck github.com/zee-ahmed/kubemngr for issues.", uname.Sysname)
-		os.Exit(0)
+	err := installBinary(ctx, binary, version)
+	if err != nil && !errors.Is(err, kubemngr.ErrAlreadyInstalled) {
+		return err
 	}
-	if uname.Machine != "arm" && uname.Machine != "arm64" && uname.Machine != "x86_64" {
-		fmt.Printf("Unsupported arch: %s\nCheck github.com/zee-ahmed/kubemngr for issues.", uname.Machine)
-		os.Exit(0)
+
+	// The plugin has to match the version kubectl resolved to
+	resolved, resolveErr := mngr.ResolveRelease(version)
+	if resolveErr != nil {
+		return resolveErr
+	}
+	if convertErr := installBinary(ctx, kubemngr.ConvertBinary, resolved); convertErr != nil {
+		if !errors.Is(convertErr, kubemngr.ErrAlreadyInstalled) {
+			return convertErr
+		}
+		if err == nil {
+			fmt.Println(convertErr)
+		}
 	}
 
-	var sys = strings.ToLower(uname.Sysname)
-	var machine string
-	if uname.Machine == "x86_64" {
-		machine = "amd64"
-	} else {
-		machine = strings.ToLower(uname.Machine)
+	// Point kubectl convert at the new plugin when its kubectl is in use
+	if !link || installDryRun {
+		return err
+	}
+	if active, _ := mngr.Active(); active == resolved {
+		if linkErr := mngr.UseBinary(kubemngr.ConvertBinary, resolved); linkErr != nil {
+			warnf("unable to link kubectl-convert %s: %v", resolved, linkErr)
+		}
+	}
+
+	return err
+}
+
+// installBinary - downloads and installs version of name, such as kubectl or kubeadm
+func installBinary(ctx context.Context, name, version string) error {
+	opts := kubemngr.InstallOptions{
+		Version:         version,
+		Binary:          name,
+		Force:           installForce,
+		DryRun:          installDryRun,
+		From:            installFrom,
+		SkipChecksum:    skipChecksum,
+		Rosetta:         rosetta,
+		Retries:         retries,
+		DownloadTimeout: downloadTimeout,
+		OS:              installOS,
+		Arch:            installArch,
+	}
+
+	if checkSignature {
+		opts.SignatureKey = viper.GetString("signature_key")
+		if opts.SignatureKey == "" {
+			return errors.New("--verify-signature needs a trusted key, set --signature-key")
+		}
 	}
 
-	// Check to make sure the file is a binary before moving the contents over to the user's home dir
-	url := "https://storage.googleapis.com/kubernetes-release/release/%v/bin/%v/%v/kubectl"
-	client := getter.Client{
-		Src:              fmt.Sprintf(url, version, sys, machine),
-		Dst:              kubectl,
-		ProgressListener: defaultProgressBar,
+	// Without --verify-exec the check only runs for this machine's own OS and arch
+	if verifyExecSet {
+		opts.VerifyExec = &verifyExec
+	}
+
+	if showProgress() {
+		progress, err := progressTracker()
+		if err != nil {
+			return err
+		}
+		opts.Progress = progress
+	}
+
+	installed, err := mngr.Install(ctx, opts)
+	if errors.Is(err, kubemngr.ErrNoDarwinArm64Build) {
+		return fmt.Errorf("%w, pass --rosetta to install the darwin/amd64 build instead", err)
 	}
-	fmt.Printf("Downloading %v\n", client.Src)
-	err = client.Get()
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+
+	warnIfEOL(installed.Version)
+
+	if installDryRun {
+		action := "install"
+		if installed.Replaced {
+			action = "reinstall"
+		}
+		fmt.Printf("Would %s %s %s for %s/%s\n  from: %s\n  to:   %s\n", action, name, installed.Version, installed.OS, installed.Arch, installed.Source, installed.Path)
+		return nil
+	}
+
+	action := "Installed"
+	if installed.Replaced {
+		action = "Reinstalled"
 	}
 
-	// elf - application/x-executable check
-	mime, _, err := mimetype.DetectFile(kubectl)
-	if mime != "application/octet-stream" && mime != "application/x-executable" {
-		fmt.Printf("The downloaded binary is not in the expected format. Please check the version and try again.")
-		os.Remove(kubectl)
-		os.Exit(1)
+	if installed.OS != runtime.GOOS || installed.Arch != runtime.GOARCH {
+		fmt.Printf("%s %s %s for %s/%s at %s\n", action, name, installed.Version, installed.OS, installed.Arch, installed.Path)
+	} else {
+		fmt.Printf("%s %s %s\n", action, name, installed.Version)
 	}
 
-	// Set executable permissions on the kubectl binary
-	if err := os.Chmod(kubectl, 0755); err != nil {
-		log.Fatal(err)
+	// Something to record and compare against the release page
+	if !quiet {
+		verified := "not checked against a published checksum"
+		if installed.Verified {
+			verified = "matches the published checksum"
+		}
+		fmt.Printf("  sha256: %s (%s)\n", installed.SHA256, verified)
 	}
 
 	return nil
 }
 
-type uname struct {
-	Sysname string
-	Machine string
+// warnIfEOL - warns when version's minor release no longer gets fixes. Not
+// knowing is no reason to stop an install, so errors are only logged.
+func warnIfEOL(version string) {
+	cycle, err := mngr.EOL(version)
+	if err != nil {
+		debugf("Unable to check whether kubectl %s is end of life: %v", version, err)
+		return
+	}
+
+	if cycle != nil && cycle.Past(time.Now()) {
+		warnf("Kubernetes %s reached end of life on %s and no longer gets security fixes", cycle.Minor, cycle.EOL.Format("2006-01-02"))
+	}
 }
 
-func getOSInfo() uname {
-	var utsname unix.Utsname
+// installAll - installs each version, carrying on past failures, and prints a
+// summary of how many were installed, skipped and failed. link is passed on to DownloadKubectl.
+func installAll(ctx context.Context, versions []string, parallel int, link bool) *batchSummary {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	var (
+		summary batchSummary
+		lock    sync.Mutex
+		wg      sync.WaitGroup
+	)
 
-	if err := unix.Uname(&utsname); err != nil {
-		fmt.Printf("Uname: %v", err)
+	slots := make(chan struct{}, parallel)
+	for _, version := range versions {
+		slots <- struct{}{}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+
+		go func(version string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			err := DownloadKubectl(ctx, version, link)
+
+			lock.Lock()
+			defer lock.Unlock()
+			switch {
+			case err == nil:
+				summary.succeeded++
+			case errors.Is(err, kubemngr.ErrAlreadyInstalled):
+				fmt.Println(err)
+				summary.skipped++
+			default:
+				fmt.Printf("Failed to install %s %s: %v\n", binary, version, err)
+				summary.failed++
+			}
+		}(version)
 	}
+	wg.Wait()
 
-	return uname{
-		Sysname: string(bytes.Trim(utsname.Sysname[:], "\x00")),
-		Machine: string(bytes.Trim(utsname.Machine[:], "\x00")),
+	if installDryRun {
+		summary.print("Would install", "already installed")
+	} else {
+		summary.print("Installed", "already installed")
 	}
+
+	return &summary
 }
@@ -19,125 +19,336 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"log"
-	"net/http"
 	"os"
-	"regexp"
+	"path/filepath"
 	"sort"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	"github.com/spf13/cobra"
-)
-
-const (
-	binaryListURL = "https://api.github.com/repos/kubernetes/kubernetes/releases?per_page=100"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
 )
 
 var (
-	remote bool
+	remote  bool
+	output  string
+	sortBy  string
+	listAll bool
+
+	listExitCode bool
 )
 
-type kubectlVersion struct {
-	Version version.Version
+// installedVersion is how an installed version is reported by list --output=json
+type installedVersion struct {
+	Version  string    `json:"version"`
+	Path     string    `json:"path"`
+	Active   bool      `json:"active"`
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+	OS       string    `json:"os"`
+	Arch     string    `json:"arch"`
+	Convert  bool      `json:"convert,omitempty"`
+	Aliases  []string  `json:"aliases,omitempty"`
+	SHA256   string    `json:"sha256,omitempty"`
+	Source   string    `json:"source,omitempty"`
 }
 
-func (kc *kubectlVersion) UnmarshalJSON(b []byte) error {
-	aux := &struct {
-		TagName string `json:"tag_name"`
-	}{}
-
-	if err := json.Unmarshal(b, &aux); err != nil {
-		log.Fatal(err)
-	}
-	version, err := version.NewVersion(aux.TagName)
-	if err != nil {
-		log.Fatal(err)
-	}
-	kc.Version = *version
-	return nil
+// remoteVersion is how an available version is reported by list --remote --output=json
+type remoteVersion struct {
+	Version string `json:"version"`
 }
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List installed kubectl binary versions. For available versions, see --remote",
+	Long: `List installed kubectl binary versions. For available versions, see --remote.
+Alpha, beta and rc versions are only listed with --pre.
+
+With --quiet only the versions are printed, one per line, for piping into other
+commands:
+
+  kubemngr list -q | xargs -n1 kubemngr verify`,
 	Run: func(cmd *cobra.Command, args []string) {
-		var versions []kubectlVersion
+		if output != "table" && output != "json" {
+			fatalf("unknown output format %q, expected table or json", output)
+		}
+		if sortBy != "version" && sortBy != "size" && sortBy != "date" {
+			fatalf("unknown sort order %q, expected version, size or date", sortBy)
+		}
+		if remote && sortBy != "version" {
+			fatal("remote versions can only be sorted by version")
+		}
+		if remote && listAll {
+			fatal("--all lists installed builds for other platforms and can't be used with --remote")
+		}
+
+		var versions []*version.Version
+		var active string
 		if remote {
-			fmt.Println("Fetching remote versions ...")
-			versions = fetchRemoteVersions()
+			if output == "table" {
+				infof("Fetching remote versions ...")
+			}
+			var err error
+			versions, err = remoteVersions()
+			if err != nil {
+				fatal(err)
+			}
 		} else {
+			if err := kubemngr.ValidateBinary(binary); err != nil {
+				fatal(err)
+			}
 			versions = fetchLocalVersions()
-
-			if len(versions) > 0 {
-				fmt.Println("Installed kubectl versions:")
-			} else {
-				fmt.Println("No versions installed. See 'kubemngr list --remote' for available versions.")
+			if listAll {
+				versions = fetchAllLocalVersions()
 			}
+			active, _ = mngr.ActiveBinary(binary)
 		}
 
-		re := regexp.MustCompile(`-rc.1|-beta.2|-beta.1|-alpha.3|-alpha.2|-alpha.1|-rc.2|-rc.3`)
+		// Installed or not, alpha, beta and rc versions are only listed with --pre
+		filtered := []*version.Version{}
 		for _, version := range versions {
-			if !re.MatchString(version.Version.String()) {
-				fmt.Println(version.Version.Original())
+			if pre || version.Prerelease() == "" {
+				filtered = append(filtered, version)
+			}
+		}
+		versions = filtered
+
+		if listExitCode && len(versions) == 0 {
+			defer os.Exit(1)
+		}
+
+		if output == "json" {
+			if err := printVersionsJSON(versions, active); err != nil {
+				fatal(err)
+			}
+			return
+		}
+
+		if remote {
+			for _, version := range versions {
+				fmt.Println(version.Original())
+			}
+			return
+		}
+
+		if quiet {
+			if err := printVersionsQuiet(versions, active); err != nil {
+				fatal(err)
+			}
+			return
+		}
+
+		if len(versions) == 0 {
+			fmt.Println("No versions installed. See 'kubemngr list --remote' for available versions.")
+			return
+		}
+
+		installed, err := installedVersions(versions, active)
+		if err != nil {
+			fatal(err)
+		}
+
+		fmt.Printf("Installed %s versions:\n", binary)
+
+		// Only take up columns for kubectl-convert and aliases when there are any
+		convert, aliased := false, false
+		for _, v := range installed {
+			convert = convert || v.Convert
+			aliased = aliased || len(v.Aliases) > 0
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, v := range installed {
+			fmt.Fprintf(w, "%s\t", v.Version)
+			if listAll {
+				fmt.Fprintf(w, "%s/%s\t", v.OS, v.Arch)
 			}
+			fmt.Fprintf(w, "%s\t%s", formatBytes(v.Size), v.Modified.Format("2006-01-02 15:04"))
+			switch {
+			case v.Convert:
+				fmt.Fprint(w, "\t+convert")
+			case convert:
+				fmt.Fprint(w, "\t")
+			}
+			if aliased {
+				fmt.Fprint(w, "\t"+strings.Join(v.Aliases, ","))
+			}
+			if v.Active {
+				fmt.Fprint(w, "\t"+green("(active)"))
+			}
+			fmt.Fprintln(w)
 		}
+		w.Flush()
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(listCmd)
 	listCmd.Flags().BoolVar(&remote, "remote", false, "Get versions from remote")
+	listCmd.Flags().BoolVar(&refreshIndex, "refresh", false, "With --remote, fetch the version list even if the cached one is recent")
+	listCmd.Flags().StringVarP(&output, "output", "o", "table", "Output format: table or json")
+	listCmd.Flags().StringVar(&binary, "binary", kubemngr.DefaultBinary, "Release binary to list the installed versions of: kubectl, kubeadm, kubelet or kubectl-convert")
+	listCmd.Flags().BoolVar(&pre, "pre", false, "Include alpha, beta and rc versions")
+	listCmd.Flags().BoolVar(&listAll, "all", false, "Include builds for other platforms installed with --os or --arch")
+	listCmd.Flags().BoolVar(&listExitCode, "exit-code", false, "Exit with status 1 when no versions are listed")
+	listCmd.Flags().StringVar(&sortBy, "sort", "version", "Sort installed versions by version, size (largest first) or date (oldest first)")
 }
 
-// fetchLocalVersions - List available installed kubectl versions
-func fetchLocalVersions() []kubectlVersion {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		log.Fatal(err)
+// printVersionsJSON - writes versions to stdout as a JSON array, including
+// file details for installed versions
+func printVersionsJSON(versions []*version.Version, active string) error {
+	var out interface{}
+	if remote {
+		list := []remoteVersion{}
+		for _, v := range versions {
+			list = append(list, remoteVersion{Version: v.Original()})
+		}
+		out = list
+	} else {
+		list, err := installedVersions(versions, active)
+		if err != nil {
+			return err
+		}
+		out = list
 	}
 
-	kubectl, err := ioutil.ReadDir(homeDir + "/.kubemngr/")
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// printVersionsQuiet - prints each installed version once in --sort order,
+// with nothing else so the output can be piped
+func printVersionsQuiet(versions []*version.Version, active string) error {
+	installed, err := installedVersions(versions, active)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	list := []kubectlVersion{}
-	for _, files := range kubectl {
-		file := files.Name()
-		name, err := version.NewVersion(strings.Replace(file, "kubectl-", "", -1))
-		if err != nil {
-			log.Fatal(err)
+	printed := map[string]bool{}
+	for _, v := range installed {
+		if !printed[v.Version] {
+			printed[v.Version] = true
+			fmt.Println(v.Version)
 		}
-		list = append(list, kubectlVersion{Version: *name})
 	}
 
-	return list
+	return nil
 }
 
-// fetchRemoteVersions lists Kubectl binaries available at the configured remote location
-func fetchRemoteVersions() []kubectlVersion {
-	res, err := http.Get(binaryListURL)
+// installedVersions - looks up the file details of each installed version,
+// including builds for other platforms with --all, and orders them by --sort
+func installedVersions(versions []*version.Version, active string) ([]installedVersion, error) {
+	all, err := listInstalled()
+	if err != nil {
+		return nil, err
+	}
+
+	wanted := map[string]bool{}
+	for _, v := range versions {
+		wanted[v.Original()] = true
+	}
+
+	aliases, err := mngr.Aliases()
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+	names := map[string][]string{}
+	for name, v := range aliases {
+		names[v] = append(names[v], name)
+	}
+	for _, list := range names {
+		sort.Strings(list)
+	}
+
+	list := []installedVersion{}
+	for _, info := range all {
+		v := info.Version.Original()
+		if !wanted[v] {
+			continue
+		}
+
+		// Only the build for this machine can be the one in use
+		host := info.Path == kubemngr.BinaryPathFor(filepath.Dir(info.Path), binary, v)
+
+		// The kubectl-convert plugin is only ever installed for this machine
+		convert := false
+		if host && binary == kubemngr.DefaultBinary {
+			_, err := mngr.PathFor(kubemngr.ConvertBinary, v)
+			convert = err == nil
+		}
+
+		list = append(list, installedVersion{
+			Version:  v,
+			Path:     info.Path,
+			Active:   v == active && host,
+			Size:     info.Size,
+			Modified: info.Modified,
+			OS:       info.OS,
+			Arch:     info.Arch,
+			Convert:  convert,
+			Aliases:  names[v],
+			SHA256:   info.SHA256,
+			Source:   info.Source,
+		})
+	}
+
+	// versions are already in version order
+	switch sortBy {
+	case "size":
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Size > list[j].Size })
+	case "date":
+		sort.SliceStable(list, func(i, j int) bool { return list[i].Modified.Before(list[j].Modified) })
+	}
+
+	return list, nil
+}
+
+// listInstalled - lists the installed versions of --binary, including builds
+// for other platforms with --all
+func listInstalled() ([]kubemngr.InstalledVersion, error) {
+	switch {
+	case binary != kubemngr.DefaultBinary:
+		return mngr.ListBinary(binary)
+	case listAll:
+		return mngr.ListAll()
 	}
-	defer res.Body.Close()
 
-	body, err := ioutil.ReadAll(res.Body)
+	return mngr.List()
+}
+
+// fetchLocalVersions - List available installed versions of --binary, kubectl by default
+func fetchLocalVersions() []*version.Version {
+	installed, err := mngr.ListBinary(binary)
 	if err != nil {
-		log.Fatal(err)
+		fatal(err)
 	}
 
-	list := []kubectlVersion{}
-	jsonErr := json.Unmarshal(body, &list)
-	if jsonErr != nil {
-		log.Fatal(jsonErr)
+	list := []*version.Version{}
+	for _, v := range installed {
+		list = append(list, v.Version)
+	}
+
+	return list
+}
+
+// fetchAllLocalVersions - lists the versions installed for any platform, once each
+func fetchAllLocalVersions() []*version.Version {
+	installed, err := mngr.ListAll()
+	if err != nil {
+		fatal(err)
 	}
 
-	sort.Slice(list, func(i, j int) bool {
-		return list[i].Version.GreaterThan(&list[j].Version)
-	})
+	seen := map[string]bool{}
+	list := []*version.Version{}
+	for _, v := range installed {
+		if !seen[v.Version.Original()] {
+			seen[v.Version.Original()] = true
+			list = append(list, v.Version)
+		}
+	}
 
 	return list
 }
@@ -0,0 +1,77 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-version"
+	"github.com/spf13/cobra"
+)
+
+var (
+	remoteLimit      int
+	remoteStableOnly bool
+
+	// refreshIndex fetches the remote version list even when the cached one is fresh
+	refreshIndex bool
+)
+
+// pre includes alpha, beta and rc versions when listing or resolving versions
+var pre bool
+
+var listRemoteCmd = &cobra.Command{
+	Use:   "list-remote",
+	Short: "List kubectl versions available to install",
+	Run: func(cmd *cobra.Command, args []string) {
+		versions, err := remoteVersions()
+		if err != nil {
+			fatal(err)
+		}
+
+		count := 0
+		for _, version := range versions {
+			if remoteLimit > 0 && count >= remoteLimit {
+				break
+			}
+			if !pre && version.Prerelease() != "" {
+				continue
+			}
+
+			fmt.Println(version.Original())
+			count++
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listRemoteCmd)
+	listRemoteCmd.Flags().IntVar(&remoteLimit, "limit", 0, "Maximum number of versions to show (0 shows all)")
+	listRemoteCmd.Flags().BoolVar(&pre, "pre", false, "Include alpha, beta and rc versions")
+	listRemoteCmd.Flags().BoolVar(&remoteStableOnly, "stable-only", false, "Exclude alpha, beta and rc versions")
+	listRemoteCmd.Flags().MarkDeprecated("stable-only", "pre-releases are excluded unless --pre is given")
+	listRemoteCmd.Flags().BoolVar(&refreshIndex, "refresh", false, "Fetch the version list even if the cached one is recent")
+}
+
+// remoteVersions - lists the versions available to install, refreshing the cache with --refresh
+func remoteVersions() ([]*version.Version, error) {
+	if refreshIndex {
+		return mngr.UpdateIndex()
+	}
+
+	return mngr.RemoteVersions()
+}
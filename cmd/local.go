@@ -0,0 +1,168 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+// versionFileName pins a kubectl version for a directory and everything below it
+const versionFileName = ".kubemngr-version"
+
+// toolVersionsFileName is asdf's version file, its kubectl line is honoured too
+const toolVersionsFileName = ".tool-versions"
+
+var localCmd = &cobra.Command{
+	Use:   "local [version]",
+	Short: "Pin a kubectl version for the current directory",
+	Long: `Pin a kubectl version for the current directory by writing a .kubemngr-version file.
+The nearest .kubemngr-version file in the current directory or its parents takes precedence
+over the version selected with 'kubemngr use'. A kubectl line in an asdf .tool-versions file
+pins the version the same way. Without a version, print the pinned version.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			version, file, err := pinnedVersion()
+			if err != nil {
+				fatal(err)
+			}
+			if version == "" {
+				fmt.Fprintf(os.Stderr, "No %s file found\n", versionFileName)
+				os.Exit(1)
+			}
+
+			fmt.Printf("%s (set by %s)\n", version, file)
+			return
+		}
+
+		if err := PinKubectlVersion(args[0]); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(localCmd)
+	localCmd.ValidArgsFunction = completeInstalledVersions
+}
+
+// PinKubectlVersion - writes a version file for version in the current directory
+func PinKubectlVersion(version string) error {
+	version = kubemngr.NormalizeVersion(version)
+	warnIfNotInstalled(version)
+
+	if err := ioutil.WriteFile(versionFileName, []byte(version+"\n"), 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("Pinned kubectl %s in %s\n", version, versionFileName)
+
+	return nil
+}
+
+// pinnedVersion - returns the version from the nearest version file and the file's path,
+// or an empty version when there is none. Like asdf, a .tool-versions file without a
+// kubectl line doesn't stop the search.
+func pinnedVersion() (string, string, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "", "", err
+	}
+
+	for {
+		file := filepath.Join(dir, versionFileName)
+		if version, found, err := readVersionFile(file); found || err != nil {
+			return version, file, err
+		}
+
+		file = filepath.Join(dir, toolVersionsFileName)
+		if version, found, err := readToolVersions(file); found || err != nil {
+			return version, file, err
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", nil
+		}
+		dir = parent
+	}
+}
+
+// readVersionFile - reads the version from a .kubemngr-version file, reporting
+// whether the file exists
+func readVersionFile(file string) (string, bool, error) {
+	content, err := readRegularFile(file)
+	if content == nil || err != nil {
+		return "", false, err
+	}
+
+	version := strings.TrimSpace(string(content))
+	if version == "" {
+		return "", true, fmt.Errorf("%s is empty", file)
+	}
+
+	return kubemngr.NormalizeVersion(version), true, nil
+}
+
+// readToolVersions - reads the kubectl version from an asdf .tool-versions file,
+// reporting whether it has one. Lines for other tools and comments are ignored,
+// and of several versions listed for kubectl the first is used.
+func readToolVersions(file string) (string, bool, error) {
+	content, err := readRegularFile(file)
+	if content == nil || err != nil {
+		return "", false, err
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "kubectl" {
+			continue
+		}
+
+		// asdf's system, ref: and path: versions don't name a release kubemngr can install
+		version := fields[1]
+		if version == "system" || strings.HasPrefix(version, "ref:") || strings.HasPrefix(version, "path:") {
+			return "", false, nil
+		}
+
+		return kubemngr.NormalizeVersion(version), true, nil
+	}
+
+	return "", false, nil
+}
+
+// readRegularFile - returns the content of file, or nil when it doesn't exist
+// or is a directory
+func readRegularFile(file string) ([]byte, error) {
+	info, err := os.Stat(file)
+	if err != nil || info.IsDir() {
+		return nil, nil
+	}
+
+	return ioutil.ReadFile(file)
+}
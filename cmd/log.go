@@ -0,0 +1,84 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+)
+
+// logLevel orders log messages by importance
+type logLevel int
+
+const (
+	levelDebug logLevel = iota
+	levelInfo
+	levelWarn
+	levelError
+)
+
+// currentLevel - the least important level that is logged, set by --verbose and --quiet
+func currentLevel() logLevel {
+	switch {
+	case verbose:
+		return levelDebug
+	case quiet:
+		return levelWarn
+	}
+
+	return levelInfo
+}
+
+// logf - writes a message at level to stderr, keeping stdout for results
+func logf(level logLevel, format string, args ...interface{}) {
+	if level < currentLevel() {
+		return
+	}
+
+	switch level {
+	case levelWarn:
+		format = warning(os.Stderr) + " " + format
+	case levelError:
+		format = colorize(os.Stderr, colorRed, "Error:") + " " + format
+	}
+
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+}
+
+func debugf(format string, args ...interface{}) { logf(levelDebug, format, args...) }
+func infof(format string, args ...interface{})  { logf(levelInfo, format, args...) }
+func warnf(format string, args ...interface{})  { logf(levelWarn, format, args...) }
+func errorf(format string, args ...interface{}) { logf(levelError, format, args...) }
+
+// fatal - logs v as an error and exits, like log.Fatal
+func fatal(v ...interface{}) {
+	errorf("%s", fmt.Sprint(v...))
+	os.Exit(1)
+}
+
+// fatalf - logs an error and exits, like log.Fatalf
+func fatalf(format string, args ...interface{}) {
+	errorf(format, args...)
+	os.Exit(1)
+}
+
+// cliLogger passes the messages of the kubemngr package through the leveled log
+type cliLogger struct{}
+
+func (cliLogger) Debugf(format string, args ...interface{}) { debugf(format, args...) }
+func (cliLogger) Infof(format string, args ...interface{})  { infof(format, args...) }
+func (cliLogger) Warnf(format string, args ...interface{})  { warnf(format, args...) }
@@ -0,0 +1,104 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+var (
+	migrateFrom   string
+	migrateDryRun bool
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate --from asdf",
+	Short: "Copy kubectl versions installed by another version manager",
+	Long: `Copy the kubectl versions installed by another version manager into kubemngr.
+Only asdf's kubectl plugin is supported: every version under
+$ASDF_DATA_DIR/installs/kubectl (~/.asdf by default) is checked to be a working
+kubectl for this machine and copied in, as 'install --from' would. Versions
+kubemngr already has are skipped. asdf's own files are left alone.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if migrateFrom != "asdf" {
+			fatalf("unable to migrate from %q, only asdf is supported", migrateFrom)
+		}
+
+		installs, err := kubemngr.AsdfInstalls()
+		if err != nil {
+			fatal(err)
+		}
+		if len(installs) == 0 {
+			fmt.Println("No kubectl versions installed by asdf")
+			return
+		}
+
+		if !migrateDryRun {
+			unlock, err := mngr.Lock()
+			if err != nil {
+				fatal(err)
+			}
+			defer unlock()
+		}
+
+		var summary batchSummary
+		for _, install := range installs {
+			installed, err := mngr.Install(cmd.Context(), kubemngr.InstallOptions{
+				Version: kubemngr.NormalizeVersion(install.Version),
+				From:    install.Path,
+				DryRun:  migrateDryRun,
+			})
+
+			switch {
+			case errors.Is(err, kubemngr.ErrAlreadyInstalled):
+				fmt.Printf("Skipping %s: %v\n", install.Path, err)
+				summary.skipped++
+			case err != nil:
+				fmt.Printf("Failed to migrate %s: %v\n", install.Path, err)
+				summary.failed++
+			case migrateDryRun:
+				fmt.Printf("Would migrate kubectl %s from %s\n", installed.Version, install.Path)
+				summary.succeeded++
+			default:
+				fmt.Printf("Migrated kubectl %s from %s\n", installed.Version, install.Path)
+				summary.succeeded++
+			}
+		}
+
+		if migrateDryRun {
+			summary.print("Would migrate", "already installed")
+			return
+		}
+		summary.print("Migrated", "already installed")
+
+		rehashIfEnabled()
+		summary.exit()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().StringVar(&migrateFrom, "from", "", "Version manager to migrate from: asdf")
+	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Show what would be migrated without copying anything")
+	migrateCmd.Flags().BoolVar(&ignoreErrors, "ignore-errors", false, "Exit 0 even if some versions failed to migrate")
+	migrateCmd.MarkFlagRequired("from")
+}
@@ -0,0 +1,69 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+// configPath - returns the path of the config file, $XDG_CONFIG_HOME/kubemngr/config.yaml
+func configPath() (string, error) {
+	configHome, err := kubemngr.XDGDir("XDG_CONFIG_HOME", ".config")
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(configHome, "kubemngr", "config.yaml"), nil
+}
+
+// cachePath - returns $XDG_CACHE_HOME/kubemngr without creating it
+func cachePath() (string, error) {
+	cacheHome, err := kubemngr.XDGDir("XDG_CACHE_HOME", ".cache")
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cacheHome, "kubemngr"), nil
+}
+
+// cacheDir - returns $XDG_CACHE_HOME/kubemngr, creating it if missing
+func cacheDir() (string, error) {
+	dir, err := cachePath()
+	if err != nil {
+		return "", err
+	}
+
+	if err := kubemngr.EnsureDir(dir); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// onPath - reports whether dir is one of the directories in $PATH
+func onPath(dir string) bool {
+	for _, entry := range filepath.SplitList(os.Getenv("PATH")) {
+		if filepath.Clean(entry) == filepath.Clean(dir) {
+			return true
+		}
+	}
+
+	return false
+}
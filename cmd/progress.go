@@ -0,0 +1,182 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	getter "github.com/hashicorp/go-getter"
+)
+
+const (
+	progressBar   = "bar"
+	progressPlain = "plain"
+	progressJSON  = "json"
+
+	// plainProgressInterval keeps plain progress readable in CI logs
+	plainProgressInterval = 5 * time.Second
+
+	// jsonProgressInterval is how often a JSON progress update is written
+	jsonProgressInterval = time.Second
+)
+
+// progressMode is how download progress is shown: bar, plain or json
+var progressMode string
+
+// noProgress hides download progress altogether
+var noProgress bool
+
+// showProgress - reports whether downloads show progress. The bar is only
+// drawn when stderr is a terminal, plain and json progress are meant for logs
+// and are written wherever stderr goes.
+func showProgress() bool {
+	if quiet || noProgress {
+		return false
+	}
+
+	return progressMode != progressBar || isTerminal(os.Stderr)
+}
+
+// progressTracker - returns the tracker for --progress
+func progressTracker() (getter.ProgressTracker, error) {
+	switch progressMode {
+	case progressBar:
+		return defaultProgressBar, nil
+	case progressPlain:
+		return &lineProgress{interval: plainProgressInterval}, nil
+	case progressJSON:
+		return &lineProgress{interval: jsonProgressInterval, json: true}, nil
+	}
+
+	return nil, fmt.Errorf("invalid --progress %q, expected bar, plain or json", progressMode)
+}
+
+// lineProgress writes a full line to stderr for every update instead of
+// redrawing a bar, for logs and programs that read the output
+type lineProgress struct {
+	interval time.Duration
+	json     bool
+
+	// lock keeps lines of parallel downloads from interleaving
+	lock sync.Mutex
+}
+
+// progressUpdate is one update written by lineProgress
+type progressUpdate struct {
+	File       string  `json:"file"`
+	Downloaded int64   `json:"downloaded"`
+	Total      int64   `json:"total"`
+	Percent    float64 `json:"percent"`
+	Rate       int64   `json:"rate"`
+	Done       bool    `json:"done"`
+}
+
+// TrackProgress - reports the progress of stream every interval until it is
+// closed, and once more when it is. total is 0 when the size isn't known.
+func (p *lineProgress) TrackProgress(src string, currentSize, totalSize int64, stream io.ReadCloser) io.ReadCloser {
+	// See ProgressBar.TrackProgress for why the total can be below currentSize
+	if totalSize < currentSize {
+		totalSize = 0
+	}
+
+	counter := &countingReader{Reader: stream, n: currentSize}
+	start := time.Now()
+	update := func(done bool) {
+		downloaded := atomic.LoadInt64(&counter.n)
+		u := progressUpdate{
+			File:       filepath.Base(src),
+			Downloaded: downloaded,
+			Total:      totalSize,
+			Done:       done,
+		}
+		if totalSize > 0 {
+			u.Percent = float64(downloaded) * 100 / float64(totalSize)
+		}
+		if elapsed := time.Since(start).Seconds(); elapsed > 0 {
+			u.Rate = int64(float64(downloaded-currentSize) / elapsed)
+		}
+		p.write(u)
+	}
+
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				update(false)
+			}
+		}
+	}()
+
+	return &readCloser{
+		Reader: counter,
+		close: func() error {
+			close(stop)
+			<-stopped
+			update(true)
+			return stream.Close()
+		},
+	}
+}
+
+// write - prints u as a JSON object or a line of text
+func (p *lineProgress) write(u progressUpdate) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.json {
+		line, err := json.Marshal(u)
+		if err == nil {
+			fmt.Fprintln(os.Stderr, string(line))
+		}
+		return
+	}
+
+	// e.g. kubectl 12.00 MiB of 45.00 MiB (27%) at 3.10 MiB/s
+	rate := formatBytes(u.Rate) + "/s"
+	if u.Total > 0 {
+		fmt.Fprintf(os.Stderr, "%s %s of %s (%.0f%%) at %s\n", u.File, formatBytes(u.Downloaded), formatBytes(u.Total), u.Percent, rate)
+	} else {
+		fmt.Fprintf(os.Stderr, "%s %s at %s\n", u.File, formatBytes(u.Downloaded), rate)
+	}
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	atomic.AddInt64(&r.n, int64(n))
+	return n, err
+}
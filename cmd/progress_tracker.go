@@ -4,8 +4,10 @@ package cmd
 
 import (
 	"io"
+	"os"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/cheggaaa/pb"
 	getter "github.com/hashicorp/go-getter"
@@ -31,12 +33,37 @@ type ProgressBar struct {
 	pbs int
 }
 
-// ProgressBarConfig .
+// ProgressBarConfig sets up a bar to show the transfer rate and ETA
+// alongside the byte counters. The pool redraws at pb.DefaultRefreshRate,
+// which keeps fast downloads from flooding the terminal.
 func ProgressBarConfig(bar *pb.ProgressBar, prefix string) {
 	bar.SetUnits(pb.U_BYTES)
+	bar.ShowSpeed = true
+	bar.ShowTimeLeft = true
 	bar.Prefix(prefix)
 }
 
+// spinnerFrames are cycled through while downloading something of unknown size
+var spinnerFrames = []string{"|", "/", "-", "\\"}
+
+// spin - shows a spinner and the bytes downloaded so far on bar in place of a
+// percentage, until stop is closed
+func spin(bar *pb.ProgressBar, prefix string, stop <-chan struct{}) {
+	ticker := time.NewTicker(pb.DefaultRefreshRate)
+	defer ticker.Stop()
+
+	for frame := 0; ; frame++ {
+		bar.Prefix(prefix + " " + spinnerFrames[frame%len(spinnerFrames)] + " " + formatBytes(bar.Get()))
+
+		select {
+		case <-stop:
+			bar.Prefix(prefix + " " + formatBytes(bar.Get()))
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // TrackProgress instantiates a new progress bar that will
 // display the progress of stream until closed.
 // total can be 0.
@@ -44,11 +71,37 @@ func (cpb *ProgressBar) TrackProgress(src string, currentSize, totalSize int64,
 	cpb.lock.Lock()
 	defer cpb.lock.Unlock()
 
+	// go-getter passes currentSize plus the Content-Length as the total, which
+	// ends up below currentSize when the server doesn't send a Content-Length.
+	// A percentage would be meaningless then, so show a spinner instead.
+	unknownSize := totalSize < currentSize
+	if unknownSize {
+		totalSize = 0
+	}
+
 	newPb := pb.New64(totalSize)
 	newPb.Set64(currentSize)
 	ProgressBarConfig(newPb, filepath.Base(src))
+
+	stopSpinner := make(chan struct{})
+	spinnerDone := make(chan struct{})
+	if unknownSize {
+		newPb.ShowBar = false
+		newPb.ShowCounters = false
+		newPb.ShowPercent = false
+		newPb.ShowTimeLeft = false
+		go func() {
+			spin(newPb, filepath.Base(src), stopSpinner)
+			close(spinnerDone)
+		}()
+	} else {
+		close(spinnerDone)
+	}
+
 	if cpb.pool == nil {
 		cpb.pool = pb.NewPool()
+		// Keep stdout for results
+		cpb.pool.Output = os.Stderr
 		cpb.pool.Start()
 	}
 	cpb.pool.Add(newPb)
@@ -58,6 +111,10 @@ func (cpb *ProgressBar) TrackProgress(src string, currentSize, totalSize int64,
 	return &readCloser{
 		Reader: reader,
 		close: func() error {
+			// Stop the spinner first so the final line shows the plain prefix
+			close(stopSpinner)
+			<-spinnerDone
+
 			cpb.lock.Lock()
 			defer cpb.lock.Unlock()
 
@@ -78,3 +135,8 @@ type readCloser struct {
 }
 
 func (c *readCloser) Close() error { return c.close() }
+
+// formatBytes renders a byte count the same way the progress bar does
+func formatBytes(n int64) string {
+	return pb.Format(n).To(pb.U_BYTES).String()
+}
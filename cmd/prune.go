@@ -0,0 +1,109 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+var (
+	pruneKeep   int
+	pruneDryRun bool
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove all but the newest installed kubectl versions",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		unlock, err := mngr.Lock()
+		if err != nil {
+			fatal(err)
+		}
+		defer unlock()
+
+		if err := PruneKubectlVersions(pruneKeep, pruneDryRun); err != nil {
+			fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pruneCmd)
+	pruneCmd.Flags().IntVar(&pruneKeep, "keep", 3, "Number of newest versions to keep")
+	viper.BindPFlag("keep", pruneCmd.Flags().Lookup("keep"))
+	pruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "Show what would be removed without removing anything")
+}
+
+// PruneKubectlVersions - removes every installed version except the newest keep
+// versions and the one currently in use
+func PruneKubectlVersions(keep int, dryRun bool) error {
+	if keep < 0 {
+		return fmt.Errorf("--keep must not be negative")
+	}
+
+	dir, err := mngr.Dir()
+	if err != nil {
+		return err
+	}
+
+	versions := fetchLocalVersions()
+	if len(versions) <= keep {
+		fmt.Println("Nothing to prune")
+		return nil
+	}
+
+	active, _ := mngr.Active()
+
+	var reclaimed int64
+	for _, v := range versions[:len(versions)-keep] {
+		version := v.Original()
+		if version == active {
+			warnf("keeping kubectl %s as it is in use", version)
+			continue
+		}
+
+		path := kubemngr.BinaryPath(dir, version)
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			fmt.Printf("Would remove kubectl %s\n", version)
+		} else {
+			fmt.Printf("Removing kubectl %s\n", version)
+			if err := mngr.Uninstall(version); err != nil {
+				return err
+			}
+		}
+		reclaimed += info.Size()
+	}
+
+	if dryRun {
+		fmt.Printf("Would reclaim %s\n", formatBytes(reclaimed))
+	} else {
+		fmt.Printf("Reclaimed %s\n", formatBytes(reclaimed))
+	}
+
+	return nil
+}
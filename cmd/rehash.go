@@ -0,0 +1,101 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var rehashCmd = &cobra.Command{
+	Use:   "rehash",
+	Short: "Write the kubectl shim again",
+	Long: `Replace the kubectl link in the kubemngr bin directory with a shim that runs
+the kubectl version pinned by .kubemngr-version, .tool-versions or the default
+every time it is called, so switching directories switches kubectl without a
+shell hook. Shims are enabled with 'kubemngr config set shims true', after
+which 'use' sets the default version instead of moving the link.
+
+install and uninstall rehash on their own. Run it by hand to repair a shim that
+was removed or edited, or after moving the kubemngr executable. Running it again
+is always safe.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if !viper.GetBool("shims") {
+			fatal("shims are disabled. Enable them with 'kubemngr config set shims true'")
+		}
+
+		unlock, err := mngr.Lock()
+		if err != nil {
+			fatal(err)
+		}
+		defer unlock()
+
+		if err := writeShim(); err != nil {
+			fatal(err)
+		}
+
+		shim := mngr.ShimPath()
+		fmt.Printf("Wrote %s\n", shim)
+
+		if !onPath(filepath.Dir(shim)) {
+			warnf(`%s is not on PATH, so the shim won't be found. Add it with eval "$(kubemngr env)"`, filepath.Dir(shim))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rehashCmd)
+}
+
+// rehashIfEnabled - rewrites the kubectl shim when shims are enabled, after
+// the versions it can pick from changed
+func rehashIfEnabled() {
+	if !viper.GetBool("shims") {
+		return
+	}
+
+	if err := writeShim(); err != nil {
+		warnf("unable to update the kubectl shim: %v. Run 'kubemngr rehash'", err)
+	}
+}
+
+// writeShim - writes the kubectl shim pointing at this kubemngr executable
+func writeShim() error {
+	exe, err := executablePath()
+	if err != nil {
+		return err
+	}
+
+	// Outside pinned directories the shim runs the default, keep the linked
+	// version in use by making it the default before the link goes
+	if defaultVersion() == "" {
+		if active, err := mngr.Active(); err == nil {
+			if _, _, err := writeConfigValue("default", active); err != nil {
+				return err
+			}
+			infof("Made the active kubectl %s the default", active)
+		} else {
+			warnf("no default kubectl version is set, the shim only works where a version is pinned. See 'kubemngr use <version>'")
+		}
+	}
+
+	return mngr.WriteShim(exe)
+}
@@ -0,0 +1,103 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	getter "github.com/hashicorp/go-getter"
+	"github.com/spf13/cobra"
+)
+
+// repairCmd represents the repair command
+var repairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Reinstall broken binaries and fix the kubectl link",
+	Long: `Reinstall the binaries recorded in installed.json that are missing or whose
+SHA256 no longer matches the one recorded when they were installed.
+
+Then fix the kubectl link when it points at a binary that no longer exists. It is
+re-pointed at the same version if that is still installed and removed otherwise.
+A missing link is re-created for the default version when that is installed.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		unlock, err := mngr.Lock()
+		if err != nil {
+			fatal(err)
+		}
+		defer unlock()
+
+		var progress getter.ProgressTracker
+		if showProgress() {
+			if progress, err = progressTracker(); err != nil {
+				fatal(err)
+			}
+		}
+
+		binaries, err := mngr.RepairBinaries(cmd.Context(), progress)
+		failed := false
+		for _, binary := range binaries {
+			problem := "corrupted"
+			if binary.Missing {
+				problem = "missing"
+			}
+			if binary.Err != nil {
+				warnf("unable to reinstall %s %s: %v", problem, binary.Name, binary.Err)
+				failed = true
+				continue
+			}
+			fmt.Printf("Reinstalled %s %s\n", problem, binary.Name)
+		}
+		if err != nil {
+			fatal(err)
+		}
+
+		// Without the default the link can still be fixed, it just isn't re-created
+		fallback, err := expandAlias(defaultVersion())
+		if err != nil {
+			warnf("unable to use the default version: %v", err)
+			fallback = ""
+		}
+
+		repair, err := mngr.RepairLink(fallback)
+		if err != nil {
+			fatal(err)
+		}
+
+		if repair.Cleared {
+			fmt.Printf("Removed %s, it pointed to missing %s\n", repair.Link, repair.Missing)
+		}
+
+		switch {
+		case repair.Version != "" && repair.Missing != "" && !repair.Cleared:
+			fmt.Printf("Re-pointed %s from missing %s to kubectl %s\n", repair.Link, repair.Missing, repair.Version)
+		case repair.Version != "":
+			fmt.Printf("Linked %s to the default kubectl %s\n", repair.Link, repair.Version)
+		case !repair.Repaired() && len(binaries) == 0:
+			fmt.Println("Nothing to repair")
+		}
+
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(repairCmd)
+}
@@ -0,0 +1,50 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var resolveCmd = &cobra.Command{
+	Use:   "resolve <version>",
+	Short: "Print the kubectl version install would pick, without installing it",
+	Long: `Resolve latest, latest-<major>.<minor> or a version range such as ~1.21 the
+same way install does and print the release it maps to, for example:
+
+  kubemngr resolve latest
+  v1.29.3
+
+A plain version is printed with the leading v release tags use.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		version, err := mngr.ResolveRelease(args[0])
+		if err != nil {
+			fatal(err)
+		}
+
+		fmt.Println(version)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(resolveCmd)
+	resolveCmd.Flags().BoolVar(&pre, "pre", false, "Allow latest and version ranges to resolve to alpha, beta and rc versions")
+	resolveCmd.ValidArgsFunction = completeRemoteVersions
+}
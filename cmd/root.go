@@ -17,31 +17,48 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
 )
 
 var cfgFile string
 var clientVersion string
+var quiet bool
+var verbose bool
+
+// mngr does the actual work of every command, configured from flags and config in initConfig
+var mngr *kubemngr.Manager
 
 var rootCmd = &cobra.Command{
 	Use:   "kubemngr",
 	Short: "A tool manage different kubectl versions inside a workspace.",
 	Long: `This tool is to help developers run different versions of kubectl within their workspace and to support working
 with different versions of Kubernetes clusters.`,
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		notifyUpdate(cmd)
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
-func Execute(version string) {
+func Execute(version, commit, date string) {
 	clientVersion = version
+	buildCommit = commit
+	buildDate = date
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cancelOnInterrupt(cancel)
 
-	if err := rootCmd.Execute(); err != nil {
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
@@ -49,26 +66,107 @@ func Execute(version string) {
 
 func init() {
 	cobra.OnInitialize(initConfig)
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "Config file (default $XDG_CONFIG_HOME/kubemngr/config.yaml)")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only print results and errors")
+	rootCmd.PersistentFlags().StringVar(&colorMode, "color", "auto", "Color output: auto, always or never. auto honours NO_COLOR")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Print extra detail about what kubemngr is doing")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 30*time.Second, "Timeout for metadata requests such as version lists and checksums")
+	viper.BindPFlag("timeout", rootCmd.PersistentFlags().Lookup("timeout"))
+	rootCmd.PersistentFlags().StringVar(&proxy, "proxy", "", "Proxy URL for all requests (defaults to HTTP_PROXY/HTTPS_PROXY)")
+	rootCmd.PersistentFlags().Bool("offline", false, "Never use the network: install only from files with --from and list remote versions from the cache")
+	viper.BindPFlag("offline", rootCmd.PersistentFlags().Lookup("offline"))
+	rootCmd.PersistentFlags().String("cacert", "", "PEM bundle of extra certificate authorities to trust, for proxies that intercept TLS")
+	viper.BindPFlag("cacert", rootCmd.PersistentFlags().Lookup("cacert"))
+	rootCmd.PersistentFlags().StringVar(&githubToken, "token", "", "GitHub token for release metadata requests, raising the API rate limit (defaults to GITHUB_TOKEN)")
+	rootCmd.PersistentFlags().BoolVar(&insecureSkipTLSVerify, "insecure-skip-tls-verify", false, "Don't verify TLS certificates. Insecure, prefer --cacert")
 }
 
 func initConfig() {
+	if err := parseColor(); err != nil {
+		fatal(err)
+	}
+
+	if err := parseProxy(); err != nil {
+		fatal(err)
+	}
+
+	configFile, err := configPath()
+	if err != nil {
+		fatal(err)
+	}
+
 	if cfgFile != "" {
 		viper.SetConfigFile(cfgFile)
+	} else if _, err := os.Stat(configFile); err == nil {
+		viper.SetConfigFile(configFile)
 	} else {
+		// Fall back to ~/.kubemngr.yaml from earlier releases
 		home, err := homedir.Dir()
 		if err != nil {
-			fmt.Println(err)
-			os.Exit(1)
+			fatal(err)
 		}
 
 		viper.AddConfigPath(home)
 		viper.SetConfigName(".kubemngr")
 	}
 
-	viper.AutomaticEnv() // read in environment variables that match
+	// read in KUBEMNGR_* environment variables, e.g. KUBEMNGR_SKIP_CHECKSUM
+	viper.SetEnvPrefix("kubemngr")
+	viper.AutomaticEnv()
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		debugf("Using config file: %s", viper.ConfigFileUsed())
+	}
+
+	applyConfig()
+
+	if err := parseTLS(); err != nil {
+		fatal(err)
+	}
+
+	if mngr, err = newManager(); err != nil {
+		fatal(err)
+	}
+}
+
+// newManager - returns a Manager configured from the flags and config file
+func newManager() (*kubemngr.Manager, error) {
+	if githubToken == "" {
+		githubToken = os.Getenv("GITHUB_TOKEN")
+	}
+
+	cache, err := cachePath()
+	if err != nil {
+		return nil, err
 	}
+
+	binaryMode, err := fileMode(viper.GetString("binary_mode"))
+	if err != nil {
+		return nil, fmt.Errorf("binary_mode: %w", err)
+	}
+	dirMode, err := fileMode(viper.GetString("dir_mode"))
+	if err != nil {
+		return nil, fmt.Errorf("dir_mode: %w", err)
+	}
+
+	opts := kubemngr.Options{
+		BinaryMode: binaryMode,
+		DirMode:    dirMode,
+		CacheDir:   cache,
+		IndexTTL:   viper.GetDuration("index_ttl"),
+		Mirror:     viper.GetString("mirror"),
+		Timeout:    timeout,
+		Proxy:      proxyURL,
+		RootCAs:    rootCAs,
+		Offline:    viper.GetBool("offline"),
+		Pre:        pre,
+		Logger:     cliLogger{},
+		UserAgent:  kubemngr.UserAgent(clientVersion),
+
+		GitHubToken:           githubToken,
+		InsecureSkipTLSVerify: insecureSkipTLSVerify,
+	}
+
+	return kubemngr.New(opts)
 }
@@ -0,0 +1,330 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/spf13/cobra"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+// clientReleaseURL is the latest kubemngr release in the GitHub API
+const clientReleaseURL = "https://api.github.com/repos/zee-ahmed/kubemngr/releases/latest"
+
+var selfUpdateCheck bool
+
+// selfUpdateCmd represents the self-update command
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update kubemngr to the latest release",
+	Long: `Check the kubemngr GitHub releases for a newer version than the one running,
+download the archive for this OS and arch, verify it against the release checksums
+and replace the running executable with it. Pass --check to only report whether an
+update is available.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		release, err := latestClientRelease(mngr.HTTPClient())
+		if err != nil {
+			fatal(err)
+		}
+
+		if !release.newerThan(clientVersion) {
+			fmt.Printf("kubemngr %s is the latest release\n", clientVersion)
+			return
+		}
+
+		if selfUpdateCheck {
+			fmt.Printf("kubemngr %s is available, this is %s. Run kubemngr self-update to update\n", release.Version, clientVersion)
+			return
+		}
+
+		exe, err := executablePath()
+		if err != nil {
+			fatal(err)
+		}
+
+		if err := updateExecutable(cmd.Context(), release, exe); err != nil {
+			if errors.Is(err, os.ErrPermission) {
+				fatalf("%v\nkubemngr is installed at %s, which you can't write to. Run sudo kubemngr self-update instead", err, exe)
+			}
+			fatal(err)
+		}
+
+		fmt.Printf("Updated kubemngr %s to %s at %s\n", clientVersion, release.Version, exe)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(selfUpdateCmd)
+	selfUpdateCmd.Flags().BoolVar(&selfUpdateCheck, "check", false, "Only report whether a newer release is available")
+}
+
+// clientRelease is a published kubemngr release and the download URLs of its assets
+type clientRelease struct {
+	Version string
+	Assets  map[string]string
+}
+
+// latestClientRelease - fetches the newest kubemngr release from GitHub with client
+func latestClientRelease(client *http.Client) (*clientRelease, error) {
+	debugf("Fetching %s", clientReleaseURL)
+	res, err := client.Get(clientReleaseURL)
+	if err != nil {
+		return nil, requestError("unable to check for kubemngr updates", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, kubemngr.GitHubResponseError("unable to check for kubemngr updates", clientReleaseURL, res)
+	}
+
+	latest := struct {
+		TagName string `json:"tag_name"`
+		Assets  []struct {
+			Name string `json:"name"`
+			URL  string `json:"browser_download_url"`
+		} `json:"assets"`
+	}{}
+	if err := json.NewDecoder(res.Body).Decode(&latest); err != nil {
+		return nil, fmt.Errorf("unable to parse the latest kubemngr release: %w", err)
+	}
+
+	release := &clientRelease{
+		Version: strings.TrimPrefix(latest.TagName, "v"),
+		Assets:  map[string]string{},
+	}
+	for _, asset := range latest.Assets {
+		release.Assets[asset.Name] = asset.URL
+	}
+
+	return release, nil
+}
+
+// newerThan - reports whether the release is newer than current. Builds
+// without a release version, such as go get installs, are always older.
+func (r *clientRelease) newerThan(current string) bool {
+	latest, err := version.NewVersion(r.Version)
+	if err != nil {
+		return false
+	}
+
+	running, err := version.NewVersion(current)
+	if err != nil {
+		return true
+	}
+
+	return latest.GreaterThan(running)
+}
+
+// archiveName - the goreleaser archive holding the build for this OS and arch
+func (r *clientRelease) archiveName() string {
+	sys := runtime.GOOS
+	switch sys {
+	case "darwin":
+		sys = "Darwin"
+	case "linux":
+		sys = "Linux"
+	}
+
+	arch := runtime.GOARCH
+	switch arch {
+	case "386":
+		arch = "i386"
+	case "amd64":
+		arch = "x86_64"
+	}
+
+	return fmt.Sprintf("kubemngr_%s_%s_%s.tar.gz", r.Version, sys, arch)
+}
+
+// checksumsName - the goreleaser file listing the SHA256 of every archive
+func (r *clientRelease) checksumsName() string {
+	return fmt.Sprintf("kubemngr_%s_checksums.txt", r.Version)
+}
+
+// executablePath - returns the kubemngr binary that is running, with symlinks resolved
+func executablePath() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("unable to find the kubemngr executable: %w", err)
+	}
+
+	return filepath.EvalSymlinks(exe)
+}
+
+// updateExecutable - downloads the release for this platform, verifies it and
+// renames it over exe. Everything is written next to exe first so the rename
+// is atomic and a failed update leaves the running binary alone.
+func updateExecutable(ctx context.Context, release *clientRelease, exe string) error {
+	archive := release.archiveName()
+	archiveURL, ok := release.Assets[archive]
+	if !ok {
+		return fmt.Errorf("kubemngr %s has no build for %s/%s", release.Version, runtime.GOOS, runtime.GOARCH)
+	}
+	checksumsURL, ok := release.Assets[release.checksumsName()]
+	if !ok {
+		return fmt.Errorf("kubemngr %s has no published checksums", release.Version)
+	}
+
+	// Find out about permissions before downloading anything
+	dir := filepath.Dir(exe)
+	tmp, err := ioutil.TempFile(dir, ".kubemngr-update-")
+	if err != nil {
+		return fmt.Errorf("unable to write to %s: %w", dir, err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	expected, err := releaseChecksum(checksumsURL, archive)
+	if err != nil {
+		return err
+	}
+
+	infof("Downloading %s", archiveURL)
+	digest, err := extractExecutable(ctx, archiveURL, tmp)
+	if err != nil {
+		return err
+	}
+	debugf("Checksum of %s: expected %s, got %s", archive, expected, digest)
+	if digest != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", archive, expected, digest)
+	}
+
+	// Flush before replacing the running binary so a full disk can't leave a truncated one
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0755); err != nil {
+		return err
+	}
+
+	// Windows won't replace a running executable but will rename it
+	if runtime.GOOS == "windows" {
+		old := exe + ".old"
+		os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			return err
+		}
+	}
+
+	return os.Rename(tmp.Name(), exe)
+}
+
+// releaseChecksum - returns the SHA256 the checksums file at url lists for name
+func releaseChecksum(url, name string) (string, error) {
+	debugf("Fetching checksums %s", url)
+	res, err := mngr.HTTPClient().Get(url)
+	if err != nil {
+		return "", requestError("unable to fetch kubemngr checksums", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to fetch kubemngr checksums: %s returned %s", url, res.Status)
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", fmt.Errorf("unable to read kubemngr checksums: %w", err)
+	}
+
+	return "", fmt.Errorf("no checksum published for %s", name)
+}
+
+// extractExecutable - streams the archive at url, writing the kubemngr binary
+// inside it to dst, and returns the SHA256 of the whole archive
+func extractExecutable(ctx context.Context, url string, dst io.Writer) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// The timeout is for metadata, the archive gets as long as it needs
+	client := mngr.HTTPClient()
+	client.Timeout = 0
+	res, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return "", requestError("unable to download kubemngr", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to download kubemngr: %s returned %s", url, res.Status)
+	}
+
+	hash := sha256.New()
+	gz, err := gzip.NewReader(io.TeeReader(res.Body, hash))
+	if err != nil {
+		return "", fmt.Errorf("unable to read kubemngr archive: %w", err)
+	}
+
+	name := "kubemngr" + kubemngr.ExeSuffix
+	found := false
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("unable to read kubemngr archive: %w", err)
+		}
+
+		if header.Typeflag == tar.TypeReg && filepath.Base(header.Name) == name {
+			if _, err := io.Copy(dst, archive); err != nil {
+				return "", fmt.Errorf("unable to extract kubemngr: %w", err)
+			}
+			found = true
+		}
+	}
+	if !found {
+		return "", fmt.Errorf("%s has no %s binary", url, name)
+	}
+
+	// Hash whatever follows the tar so the digest covers the whole file
+	if _, err := io.Copy(ioutil.Discard, io.TeeReader(res.Body, hash)); err != nil {
+		return "", fmt.Errorf("unable to download kubemngr: %w", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+// shadowingKubectl - returns the kubectl that PATH resolves to when it is not the
+// managed one, or an empty string when the managed kubectl is the one that runs
+func shadowingKubectl() (string, error) {
+	link := mngr.LinkPath()
+
+	found, err := exec.LookPath("kubectl")
+	if err != nil {
+		return "", nil
+	}
+
+	foundInfo, err := os.Stat(found)
+	if err != nil {
+		return "", nil
+	}
+
+	// Only compare against a link when it resolves, a dangling link is reported
+	// elsewhere. Until use has been run the link of earlier releases is the managed one.
+	links := []string{link}
+	if legacy, err := kubemngr.LegacyLinkPath(); err == nil {
+		links = append(links, legacy)
+	}
+	for _, link := range links {
+		if linkInfo, err := os.Stat(link); err == nil && os.SameFile(foundInfo, linkInfo) {
+			return "", nil
+		}
+	}
+
+	return found, nil
+}
+
+// warnIfShadowed - prints a warning to stderr when another kubectl comes before the managed one on PATH
+func warnIfShadowed() {
+	shadow, err := shadowingKubectl()
+	if err != nil || shadow == "" {
+		return
+	}
+
+	link := mngr.LinkPath()
+	warnf("%s is found before %s on PATH, so the kubectl managed by kubemngr is not the one that runs. Move %s ahead of %s in PATH to fix this.",
+		shadow, link, filepath.Dir(link), filepath.Dir(shadow))
+}
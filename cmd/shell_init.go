@@ -0,0 +1,141 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// The hooks keep KUBEMNGR_KUBECTL pointing at the kubectl in effect for the
+// working directory and wrap kubectl to run it. They are refreshed on every
+// directory change and after each kubemngr command, and only define functions
+// and hooks once however often they are evaluated.
+const bashHook = `_kubemngr_hook() {
+  if [ "$PWD" = "${_KUBEMNGR_PWD:-}" ]; then
+    return
+  fi
+  _KUBEMNGR_PWD="$PWD"
+  KUBEMNGR_KUBECTL="$(command kubemngr current --path -q 2>/dev/null)"
+  export KUBEMNGR_KUBECTL
+}
+
+kubectl() {
+  if [ -n "${KUBEMNGR_KUBECTL:-}" ]; then
+    "$KUBEMNGR_KUBECTL" "$@"
+  else
+    command kubectl "$@"
+  fi
+}
+
+kubemngr() {
+  command kubemngr "$@"
+  local ret=$?
+  _KUBEMNGR_PWD=
+  _kubemngr_hook
+  return $ret
+}
+
+case ";${PROMPT_COMMAND:-};" in
+  *";_kubemngr_hook;"*) ;;
+  *) PROMPT_COMMAND="_kubemngr_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}" ;;
+esac
+
+_KUBEMNGR_PWD=
+_kubemngr_hook
+`
+
+const zshHook = `_kubemngr_hook() {
+  KUBEMNGR_KUBECTL="$(command kubemngr current --path -q 2>/dev/null)"
+  export KUBEMNGR_KUBECTL
+}
+
+kubectl() {
+  if [[ -n "$KUBEMNGR_KUBECTL" ]]; then
+    "$KUBEMNGR_KUBECTL" "$@"
+  else
+    command kubectl "$@"
+  fi
+}
+
+kubemngr() {
+  command kubemngr "$@"
+  local ret=$?
+  _kubemngr_hook
+  return $ret
+}
+
+autoload -Uz add-zsh-hook
+add-zsh-hook chpwd _kubemngr_hook
+_kubemngr_hook
+`
+
+const fishHook = `function _kubemngr_hook --on-variable PWD
+    set -gx KUBEMNGR_KUBECTL (command kubemngr current --path -q 2>/dev/null)
+end
+
+function kubectl
+    if test -n "$KUBEMNGR_KUBECTL"
+        $KUBEMNGR_KUBECTL $argv
+    else
+        command kubectl $argv
+    end
+end
+
+function kubemngr
+    command kubemngr $argv
+    set -l ret $status
+    _kubemngr_hook
+    return $ret
+end
+
+_kubemngr_hook
+`
+
+var shellInitCmd = &cobra.Command{
+	Use:   "shell-init [bash|zsh|fish]",
+	Short: "Print a shell hook that switches kubectl with the directory",
+	Long: `Print a snippet that makes kubectl follow .kubemngr-version and .tool-versions files
+as you change directories, without running 'kubemngr use'. Add it to your shell's rc file:
+
+Bash:
+  eval "$(kubemngr shell-init bash)"
+
+Zsh:
+  eval "$(kubemngr shell-init zsh)"
+
+Fish:
+  kubemngr shell-init fish | source
+`,
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	Args:      cobra.ExactValidArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		switch args[0] {
+		case "bash":
+			fmt.Print(bashHook)
+		case "zsh":
+			fmt.Print(zshHook)
+		case "fish":
+			fmt.Print(fishHook)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(shellInitCmd)
+}
@@ -0,0 +1,250 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+var (
+	uninstallForce bool
+	uninstallYes   bool
+	uninstallOS    string
+	uninstallArch  string
+)
+
+var uninstallCmd = &cobra.Command{
+	Use:     "uninstall <version>...",
+	Aliases: []string{"remove"},
+	Short:   "Remove a kubectl version from machine",
+	Long: `Remove one or more kubectl versions. With several versions, those that aren't
+installed are skipped, a failure doesn't stop the rest and a summary is printed
+at the end.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		unlock, err := mngr.Lock()
+		if err != nil {
+			fatal(err)
+		}
+		defer unlock()
+
+		if len(args) == 1 {
+			if err := RemoveKubectlVersion(args[0]); err != nil {
+				fatal(err)
+			}
+			rehashIfEnabled()
+			return
+		}
+
+		summary := removeAll(args)
+		rehashIfEnabled()
+		summary.exit()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uninstallCmd)
+	uninstallCmd.Flags().BoolVar(&uninstallForce, "force", false, "Remove the version even if it is currently in use")
+	uninstallCmd.Flags().BoolVarP(&uninstallYes, "yes", "y", false, "Do not prompt for confirmation")
+	uninstallCmd.Flags().BoolVar(&ignoreErrors, "ignore-errors", false, "Exit 0 even if some of several versions failed to be removed")
+	uninstallCmd.Flags().StringVar(&binary, "binary", kubemngr.DefaultBinary, "Release binary to remove: kubectl, kubeadm, kubelet or kubectl-convert")
+	uninstallCmd.Flags().StringVar(&uninstallOS, "os", "", "Remove the build for this OS instead of this machine's")
+	uninstallCmd.Flags().StringVar(&uninstallArch, "arch", "", "Remove the build for this architecture instead of this machine's")
+}
+
+// RemoveKubectlVersion - removes specific kubectl version from machine
+func RemoveKubectlVersion(version string) error {
+	version = kubemngr.NormalizeVersion(version)
+
+	if binary != kubemngr.DefaultBinary {
+		return removeBinary(version)
+	}
+
+	if uninstallOS != "" || uninstallArch != "" {
+		return removeCrossBuild(version)
+	}
+
+	// Check if version to be removed exists
+	if _, err := mngr.Path(version); err != nil {
+		return err
+	}
+
+	active, _ := mngr.Active()
+	if active == version && !uninstallForce {
+		return fmt.Errorf("kubectl %s is currently in use. Pass --force to remove it anyway", version)
+	}
+
+	if !uninstallYes {
+		ok, err := confirm(fmt.Sprintf("Remove kubectl %s?", version))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	fmt.Printf("Removing kubectl %s\n", version)
+	if err := mngr.Uninstall(version); err != nil {
+		return err
+	}
+
+	// Don't leave the kubectl link dangling after removing the active version
+	if active == version {
+		if err := os.Remove(mngr.LinkPath()); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeAll - removes each version after asking once, carrying on past
+// failures, and prints a summary of how many were removed, skipped and failed
+func removeAll(versions []string) *batchSummary {
+	var summary batchSummary
+
+	if !uninstallYes {
+		ok, err := confirm(fmt.Sprintf("Remove kubectl %s?", strings.Join(versions, ", ")))
+		if err != nil {
+			fatal(err)
+		}
+		if !ok {
+			fmt.Println("Aborted")
+			return &summary
+		}
+		uninstallYes = true
+	}
+
+	for _, version := range versions {
+		err := RemoveKubectlVersion(version)
+		switch {
+		case err == nil:
+			summary.succeeded++
+		case errors.Is(err, kubemngr.ErrNotInstalled):
+			fmt.Println(err)
+			summary.skipped++
+		default:
+			fmt.Printf("Failed to remove %s %s: %v\n", binary, version, err)
+			summary.failed++
+		}
+	}
+
+	summary.print("Removed", "not installed")
+	return &summary
+}
+
+// removeBinary - removes version of --binary, such as kubeadm, and its link
+// when it is the one in use
+func removeBinary(version string) error {
+	if err := kubemngr.ValidateBinary(binary); err != nil {
+		return err
+	}
+	if uninstallOS != "" || uninstallArch != "" {
+		return errors.New("--os and --arch only apply to kubectl")
+	}
+
+	if _, err := mngr.PathFor(binary, version); err != nil {
+		return err
+	}
+
+	active, _ := mngr.ActiveBinary(binary)
+	if active == version && !uninstallForce {
+		return fmt.Errorf("%s %s is currently in use. Pass --force to remove it anyway", binary, version)
+	}
+
+	if !uninstallYes {
+		ok, err := confirm(fmt.Sprintf("Remove %s %s?", binary, version))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	fmt.Printf("Removing %s %s\n", binary, version)
+	if err := mngr.UninstallBinary(binary, version); err != nil {
+		return err
+	}
+
+	if active == version {
+		if err := os.Remove(mngr.LinkPathFor(binary)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeCrossBuild - removes the build of version picked by --os and --arch,
+// which is never in use when it is for another platform
+func removeCrossBuild(version string) error {
+	path, err := mngr.PlatformPath(version, uninstallOS, uninstallArch)
+	if err != nil {
+		return err
+	}
+
+	if path == kubemngr.BinaryPath(filepath.Dir(path), version) {
+		// --os and --arch named this machine
+		uninstallOS, uninstallArch = "", ""
+		return RemoveKubectlVersion(version)
+	}
+
+	if !uninstallYes {
+		ok, err := confirm(fmt.Sprintf("Remove kubectl %s (%s)?", version, filepath.Base(path)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Aborted")
+			return nil
+		}
+	}
+
+	fmt.Printf("Removing %s\n", filepath.Base(path))
+	return mngr.UninstallPlatform(version, uninstallOS, uninstallArch)
+}
+
+// confirm - asks the user a yes/no question on stdin, defaulting to no. A
+// script can't answer, so without a terminal on stdin it is an error rather
+// than a silent no.
+func confirm(question string) (bool, error) {
+	if !isTerminal(os.Stdin) {
+		return false, errors.New("refusing to prompt without a terminal, pass --yes")
+	}
+
+	fmt.Printf("%s [y/N] ", question)
+
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false, fmt.Errorf("unable to read the answer: %w", err)
+	}
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes", nil
+}
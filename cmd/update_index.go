@@ -18,44 +18,28 @@ package cmd
 
 import (
 	"fmt"
-	"log"
-	"os"
 
 	"github.com/spf13/cobra"
 )
 
-var removeCmd = &cobra.Command{
-	Use:   "remove",
-	Short: "Remove a kubectl version from machine",
+// updateIndexCmd represents the update-index command
+var updateIndexCmd = &cobra.Command{
+	Use:   "update-index",
+	Short: "Refresh the cached list of kubectl versions available to install",
+	Long: `Fetch the list of kubectl versions available to install and cache it. list-remote,
+version ranges use the cached list until it is older than index_ttl (1h by
+default), or when the remote can't be reached. Completion always uses it, however old.`,
+	Args: cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		err := RemoveKubectlVersion(args[0])
+		versions, err := mngr.UpdateIndex()
 		if err != nil {
-			log.Fatal(err)
+			fatal(err)
 		}
+
+		fmt.Printf("Updated the version index, %d versions available\n", len(versions))
 	},
 }
 
 func init() {
-	rootCmd.AddCommand(removeCmd)
-}
-
-// RemoveKubectlVersion - removes specific kubectl version from machine
-func RemoveKubectlVersion(version string) error {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	kubectlVersion := homeDir + "/.kubemngr/kubectl-" + version
-
-	// Check if version to be removed exists
-	_, err = os.Stat(kubectlVersion)
-	if err == nil {
-		fmt.Printf("Removing kubectl %s", version)
-		os.Remove(kubectlVersion)
-		return nil
-	}
-
-	fmt.Printf("kubectl %s is not installed", version)
-	return nil
+	rootCmd.AddCommand(updateIndexCmd)
 }
@@ -0,0 +1,102 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	// updateCheckFile caches the result of the last update check in the cache dir
+	updateCheckFile = "update-check.json"
+
+	// updateCheckInterval is how long a check is trusted before asking GitHub again
+	updateCheckInterval = 24 * time.Hour
+
+	// updateCheckTimeout keeps an unreachable GitHub from holding up commands
+	updateCheckTimeout = 3 * time.Second
+)
+
+// updateCheck is the cached result of the last update check
+type updateCheck struct {
+	Checked time.Time `json:"checked"`
+	Latest  string    `json:"latest"`
+}
+
+// notifyUpdate - prints a notice to stderr after cmd when a newer kubemngr is
+// out. It only runs when update_check is enabled, asks GitHub at most once a
+// day and stays silent offline, with --quiet or when stderr isn't a terminal.
+func notifyUpdate(cmd *cobra.Command) {
+	if !viper.GetBool("update_check") || viper.GetBool("offline") || quiet || !isTerminal(os.Stderr) {
+		return
+	}
+	if cmd.Hidden || cmd == selfUpdateCmd {
+		return
+	}
+
+	latest, err := latestClientVersion()
+	if err != nil {
+		debugf("Unable to check for kubemngr updates: %v", err)
+		return
+	}
+
+	release := &clientRelease{Version: latest}
+	if release.newerThan(clientVersion) {
+		infof("kubemngr %s is available, this is %s. Run kubemngr self-update to update", latest, clientVersion)
+	}
+}
+
+// latestClientVersion - returns the newest kubemngr release, from the cache
+// when it was checked in the last day
+func latestClientVersion() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, updateCheckFile)
+
+	var cached updateCheck
+	if data, err := ioutil.ReadFile(path); err == nil && json.Unmarshal(data, &cached) == nil {
+		if time.Since(cached.Checked) < updateCheckInterval {
+			return cached.Latest, nil
+		}
+	}
+
+	// Record failed checks too, so being offline doesn't mean a request every run
+	cached = updateCheck{Checked: time.Now()}
+
+	client := mngr.HTTPClient()
+	client.Timeout = updateCheckTimeout
+	release, err := latestClientRelease(client)
+	if err == nil {
+		cached.Latest = release.Version
+	}
+
+	// A failed cache write only means checking again next time
+	if data, err := json.Marshal(cached); err == nil {
+		ioutil.WriteFile(path, data, 0644)
+	}
+
+	return cached.Latest, err
+}
@@ -17,54 +17,105 @@ limitations under the License.
 package cmd
 
 import (
+	"errors"
 	"fmt"
-	"log"
-	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
 )
 
 var useCmd = &cobra.Command{
 	Use:   "use",
 	Short: "Use a specific version of one of the downloaded kubectl binaries",
+	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		err := UseKubectlBinary(args[0])
+		unlock, err := mngr.Lock()
 		if err != nil {
-			log.Fatal(err)
+			fatal(err)
+		}
+		defer unlock()
+
+		err = UseKubectlBinary(args[0])
+		if err != nil {
+			fatal(err)
 		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(useCmd)
+	useCmd.Flags().StringVar(&binary, "binary", kubemngr.DefaultBinary, "Release binary to switch: kubectl, kubeadm, kubelet or kubectl-convert")
 }
 
 // UseKubectlBinary - sets kubectl to the version specified
 func UseKubectlBinary(version string) error {
-	homeDir, err := os.UserHomeDir()
+	version, err := expandAlias(version)
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	kubectlVersion := homeDir + "/.kubemngr/kubectl-" + version
-	kubectlLink := homeDir + "/.local/bin/kubectl"
+	if binary != kubemngr.DefaultBinary {
+		return useBinary(version)
+	}
 
-	_, err = os.Stat(kubectlVersion)
-	if os.IsNotExist(err) {
-		log.Printf("kubectl %s does not exist", version)
-		os.Exit(1)
+	if viper.GetBool("shims") {
+		return useWithShim(version)
 	}
 
-	if _, err := os.Lstat(kubectlLink); err == nil {
-		os.Remove(kubectlLink)
+	err = mngr.Use(version)
+	if errors.Is(err, kubemngr.ErrNotInstalled) {
+		return fmt.Errorf("%w. Run 'kubemngr install %s' first", err, version)
 	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Now using kubectl %s\n", version)
 
-	err = os.Symlink(kubectlVersion, kubectlLink)
+	return nil
+}
+
+// useBinary - points the link for --binary, such as kubeadm, at version
+func useBinary(version string) error {
+	if err := kubemngr.ValidateBinary(binary); err != nil {
+		return err
+	}
+
+	err := mngr.UseBinary(binary, version)
+	if errors.Is(err, kubemngr.ErrNotInstalled) {
+		return fmt.Errorf("%w. Run 'kubemngr install --binary %s %s' first", err, binary, version)
+	}
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+
+	fmt.Printf("Now using %s %s\n", binary, version)
+
+	return nil
+}
+
+// useWithShim - makes version the default the kubectl shim falls back to
+// outside pinned directories, writing the shim if it is missing
+func useWithShim(version string) error {
+	if _, err := mngr.Path(version); err != nil {
+		if errors.Is(err, kubemngr.ErrNotInstalled) {
+			return fmt.Errorf("%w. Run 'kubemngr install %s' first", err, version)
+		}
+		return err
+	}
+
+	if _, _, err := writeConfigValue("default", version); err != nil {
+		return err
+	}
+
+	if !mngr.HasShim() {
+		if err := writeShim(); err != nil {
+			return err
+		}
 	}
 
-	fmt.Printf("kubectl version set to %s", version)
+	fmt.Printf("Now using kubectl %s\n", version)
 
 	return nil
 }
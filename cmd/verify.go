@@ -0,0 +1,138 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+var verifyFetch bool
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [version]...",
+	Short: "Check installed kubectl binaries against their recorded checksums",
+	Long: `Check installed kubectl binaries against their recorded checksums, every
+installed version when none is given. With several versions a failure doesn't
+stop the rest and a summary is printed at the end.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 1 {
+			summary := verifyAll(args)
+			summary.exit()
+			return
+		}
+
+		version := ""
+		if len(args) == 1 {
+			version = args[0]
+		}
+
+		corrupt, err := VerifyKubectlVersions(version, verifyFetch)
+		if err != nil {
+			fatal(err)
+		}
+		if corrupt > 0 && !ignoreErrors {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	verifyCmd.Flags().BoolVar(&ignoreErrors, "ignore-errors", false, "Exit 0 even if some versions are corrupt or couldn't be checked")
+	verifyCmd.Flags().BoolVar(&verifyFetch, "fetch", false, "Fetch the published checksum of versions with none recorded")
+}
+
+// verifyAll - verifies each version, carrying on past failures, and prints a
+// summary. Versions without a checksum to compare against count as skipped.
+func verifyAll(versions []string) *batchSummary {
+	var summary batchSummary
+
+	var results []kubemngr.VerifyResult
+	for _, version := range versions {
+		found, err := mngr.Verify(version, verifyFetch)
+		if err != nil {
+			fmt.Printf("Failed to verify kubectl %s: %v\n", version, err)
+			summary.failed++
+			continue
+		}
+		results = append(results, found...)
+	}
+
+	corrupt, unknown := reportVerifyResults(results, verifyFetch)
+	summary.succeeded = len(results) - corrupt - unknown
+	summary.skipped = unknown
+	summary.failed += corrupt
+
+	fmt.Println()
+	summary.print("OK", "unknown")
+	return &summary
+}
+
+// VerifyKubectlVersions - reports whether each installed version still matches
+// its checksum, returning the number of corrupt ones
+func VerifyKubectlVersions(version string, fetch bool) (int, error) {
+	results, err := mngr.Verify(version, fetch)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(results) == 0 {
+		fmt.Println("No versions installed")
+		return 0, nil
+	}
+
+	corrupt, _ := reportVerifyResults(results, fetch)
+	return corrupt, nil
+}
+
+// reportVerifyResults - prints a line per result and how to fix what failed,
+// returning the number of corrupt and unknown versions
+func reportVerifyResults(results []kubemngr.VerifyResult, fetch bool) (int, int) {
+	var corrupt, unknown []string
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, result := range results {
+		switch result.Status {
+		case kubemngr.VerifyOK:
+			fmt.Fprintf(w, "%s\t%s\n", result.Version, green("OK"))
+		case kubemngr.VerifyCorrupt:
+			fmt.Fprintf(w, "%s\t%s\texpected %s, got %s\n", result.Version, red("CORRUPT"), result.Expected, result.Actual)
+			corrupt = append(corrupt, result.Version)
+		default:
+			fmt.Fprintf(w, "%s\t%s\tno checksum recorded\n", result.Version, yellow("UNKNOWN"))
+			unknown = append(unknown, result.Version)
+		}
+	}
+	w.Flush()
+
+	if len(unknown) > 0 && !fetch {
+		fmt.Println("\nPass --fetch to check versions without a recorded checksum against the published ones")
+	}
+
+	if len(corrupt) > 0 {
+		fmt.Println("\nReinstall the corrupt versions with:")
+		for _, v := range corrupt {
+			fmt.Printf("  kubemngr install --force %s\n", v)
+		}
+	}
+
+	return len(corrupt), len(unknown)
+}
@@ -18,19 +18,42 @@ package cmd
 
 import (
 	"fmt"
+	"runtime"
 
 	"github.com/spf13/cobra"
 )
 
+var (
+	buildCommit string
+	buildDate   string
+)
+
 // versionCmd represents the version command
 var versionCmd = &cobra.Command{
-	Use:   "version",
-	Short: "Show the kubemngr client version",
+	Use:     "version",
+	Aliases: []string{"self-version"},
+	Short:   "Show the kubemngr client version",
+	Long: `Show the version of kubemngr itself along with the commit and date it was built
+from, the Go version and the platform it was built for. The version of the kubectl
+being managed is shown by current.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println(rootCmd.Use + " " + clientVersion)
+		fmt.Printf("  commit:     %s\n", valueOr(buildCommit, "unknown"))
+		fmt.Printf("  built:      %s\n", valueOr(buildDate, "unknown"))
+		fmt.Printf("  go version: %s\n", runtime.Version())
+		fmt.Printf("  platform:   %s/%s\n", runtime.GOOS, runtime.GOARCH)
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(versionCmd)
 }
+
+// valueOr - returns value, or fallback when it wasn't set at build time
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+
+	return value
+}
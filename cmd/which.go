@@ -0,0 +1,106 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
+)
+
+var (
+	whichAll  bool
+	whichOS   string
+	whichArch string
+)
+
+var whichCmd = &cobra.Command{
+	Use:   "which [version]",
+	Short: "Print the path of a kubectl version's binary",
+	Long: `Print the absolute path of the binary for an installed kubectl version.
+Without a version, print the path of the version currently in use. Pass --os
+or --arch for the path of a build for another platform.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		dir, err := mngr.Dir()
+		if err != nil {
+			fatal(err)
+		}
+
+		if err := kubemngr.ValidateBinary(binary); err != nil {
+			fatal(err)
+		}
+
+		switch {
+		case binary != kubemngr.DefaultBinary && (whichOS != "" || whichArch != ""):
+			fatal("--os and --arch only apply to kubectl")
+		case whichOS != "" || whichArch != "":
+			if len(args) == 0 {
+				fatal("--os and --arch need a version")
+			}
+			version, err := expandAlias(args[0])
+			if err != nil {
+				fatal(err)
+			}
+			path, err := mngr.PlatformPath(version, whichOS, whichArch)
+			if err != nil {
+				fatal(err)
+			}
+			fmt.Println(path)
+		case whichAll:
+			for _, v := range fetchLocalVersions() {
+				fmt.Println(kubemngr.BinaryPathFor(dir, binary, v.Original()))
+			}
+		case len(args) == 0 && binary != kubemngr.DefaultBinary:
+			version, err := mngr.ActiveBinary(binary)
+			if err != nil {
+				fatalf("no %s version is in use. See 'kubemngr use --binary %s <version>'", binary, binary)
+			}
+			path, err := mngr.PathFor(binary, version)
+			if err != nil {
+				fatal(err)
+			}
+			fmt.Println(path)
+		case len(args) == 0:
+			_, path, err := currentKubectl()
+			if err != nil {
+				fatal(err)
+			}
+			fmt.Println(path)
+		default:
+			version, err := expandAlias(args[0])
+			if err != nil {
+				fatal(err)
+			}
+			path, err := mngr.PathFor(binary, version)
+			if err != nil {
+				fatal(err)
+			}
+			fmt.Println(path)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(whichCmd)
+	whichCmd.Flags().BoolVar(&whichAll, "all", false, "Print the paths of every installed version")
+	whichCmd.Flags().StringVar(&binary, "binary", kubemngr.DefaultBinary, "Release binary to print the path of: kubectl, kubeadm, kubelet or kubectl-convert")
+	whichCmd.Flags().StringVar(&whichOS, "os", "", "Print the path of the build for this OS")
+	whichCmd.Flags().StringVar(&whichArch, "arch", "", "Print the path of the build for this architecture")
+	whichCmd.ValidArgsFunction = completeVersionOrAlias
+}
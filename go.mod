@@ -1,19 +1,17 @@
 module github.com/zee-ahmed/kubemngr
 
-go 1.12
+go 1.13
 
 require (
+	github.com/Masterminds/semver/v3 v3.1.1
 	github.com/cheggaaa/pb v1.0.27
-	github.com/gabriel-vasile/mimetype v0.3.18
-	github.com/hashicorp/go-cleanhttp v0.5.1 // indirect
 	github.com/hashicorp/go-getter v1.4.0
 	github.com/hashicorp/go-version v1.2.0
-	github.com/magiconair/properties v1.8.1 // indirect
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/pelletier/go-toml v1.4.0 // indirect
 	github.com/spf13/afero v1.2.2 // indirect
-	github.com/spf13/cobra v0.0.5
+	github.com/spf13/cobra v1.1.3
 	github.com/spf13/jwalterweatherman v1.1.0 // indirect
-	github.com/spf13/viper v1.4.0
+	github.com/spf13/viper v1.7.0
 	golang.org/x/sys v0.0.0-20190913121621-c3b328c6e5a7
 )
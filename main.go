@@ -5,7 +5,7 @@ Licensed under the Apache License, Version 2.0 (the "License");
 you may not use this file except in compliance with the License.
 You may obtain a copy of the License at
 
-    http://www.apache.org/licenses/LICENSE-2.0
+	http://www.apache.org/licenses/LICENSE-2.0
 
 Unless required by applicable law or agreed to in writing, software
 distributed under the License is distributed on an "AS IS" BASIS,
@@ -19,14 +19,19 @@ import (
 	"fmt"
 	"log"
 	"os"
-	"strings"
+	"path/filepath"
 
 	"github.com/zee-ahmed/kubemngr/cmd"
+	"github.com/zee-ahmed/kubemngr/pkg/kubemngr"
 )
 
 var (
 	clientVersion = "0.1.2"
 	usrLocalBin   = "/usr/local/bin"
+
+	// Set with -ldflags "-X main.commit=... -X main.date=..." when building a release
+	commit = ""
+	date   = ""
 )
 
 type paths []string
@@ -42,59 +47,75 @@ func (p paths) indexOf(element string) int {
 
 func main() {
 	// set kubemngr directory
-	homeDir, err := os.UserHomeDir()
+	kubemngrHome, err := kubemngr.DefaultHome()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	directory := homeDir + "/.kubemngr"
-	createDirectory(directory)
-	binDirectory := homeDir + "/.local/bin"
-	createDirectory(binDirectory)
+	// The directory is created with the configured dir_mode on first use
+	binDirectory := kubemngr.BinDir(kubemngrHome)
 
 	path, exists := os.LookupEnv("PATH")
 	if !exists {
 		log.Fatal("Can't access environment variable: PATH")
 	}
 
-	var paths paths = strings.Split(path, ":")
-	pathsBeforeUsrLocalBin := paths[:paths.indexOf(usrLocalBin)]
-	if pathsBeforeUsrLocalBin.indexOf(binDirectory) < 0 {
-		fmt.Printf("PATH does not give precedent to %v/.local/bin. kubectl will be executed from /usr/local/bin unless PATH is amended.\n\n", homeDir)
-
-		shell, exists := os.LookupEnv("SHELL")
-		if !exists || shell != "/bin/zsh" && shell != "/bin/bash" {
-			os.Exit(0)
-		}
+	var paths paths = filepath.SplitList(path)
+	pathsBeforeUsrLocalBin := paths
+	if i := paths.indexOf(usrLocalBin); i >= 0 {
+		pathsBeforeUsrLocalBin = paths[:i]
+	}
 
-		fmt.Printf("\tDetected shell is %v, suggested amendment:\n\t", shell)
-		if shell == "/bin/zsh" {
-			fmt.Println(`echo 'export PATH="$HOME/.local/bin:$PATH"' >> ~/.zshrc`)
-		} else if shell == "/bin/bash" {
-			fmt.Println(`echo 'export PATH="$HOME/.local/bin:$PATH"' >> ~/.bashrc`)
+	// Setups from before the bin directory keep working as long as the old
+	// ~/.local/bin/kubectl link exists, use points it at the new one
+	onPath := pathsBeforeUsrLocalBin.indexOf(binDirectory) >= 0
+	if legacyLink, err := kubemngr.LegacyLinkPath(); err == nil && pathsBeforeUsrLocalBin.indexOf(filepath.Dir(legacyLink)) >= 0 {
+		if _, err := os.Lstat(legacyLink); err == nil {
+			onPath = true
 		}
+	}
 
-		os.Exit(0)
+	// Commands run by scripts, shell hooks and completion must keep their output
+	// clean, and env prints the commands that fix PATH
+	if !onPath && !scripted(os.Args) {
+		warnPath(binDirectory)
 	}
 
-	cmd.Execute(clientVersion)
+	cmd.Execute(clientVersion, commit, date)
 }
 
-func createDirectory(dirName string) bool {
-	src, err := os.Stat(dirName)
+// scriptedCommands are run by shell hooks, completion and the kubectl shim,
+// where the PATH warning would end up in their output
+var scriptedCommands = map[string]bool{
+	"__complete":       true,
+	"__completeNoDesc": true,
+	"completion":       true,
+	"current":          true,
+	"env":              true,
+	"exec":             true,
+	"shell-init":       true,
+}
 
-	if os.IsNotExist(err) {
-		errDir := os.MkdirAll(dirName, 0755)
-		if errDir != nil {
-			panic(err)
-		}
-		return true
-	}
+// scripted - reports whether args run one of scriptedCommands
+func scripted(args []string) bool {
+	return len(args) > 1 && scriptedCommands[args[1]]
+}
 
-	if src.Mode().IsRegular() {
-		fmt.Println(dirName, "already exist as a file!")
-		return false
+// warnPath - tells the user on stderr how to put binDirectory on PATH. The
+// command still runs, the warning is no reason to fail it.
+func warnPath(binDirectory string) {
+	fmt.Fprintf(os.Stderr, "PATH does not give precedent to %v. kubectl will be executed from /usr/local/bin unless PATH is amended.\n\n", binDirectory)
+
+	shell, exists := os.LookupEnv("SHELL")
+	if !exists || shell != "/bin/zsh" && shell != "/bin/bash" {
+		return
 	}
 
-	return false
+	fmt.Fprintf(os.Stderr, "\tDetected shell is %v, suggested amendment:\n\t", shell)
+	if shell == "/bin/zsh" {
+		fmt.Fprintln(os.Stderr, `echo 'eval "$(kubemngr env)"' >> ~/.zshrc`)
+	} else if shell == "/bin/bash" {
+		fmt.Fprintln(os.Stderr, `echo 'eval "$(kubemngr env)"' >> ~/.bashrc`)
+	}
+	fmt.Fprintln(os.Stderr)
 }
@@ -0,0 +1,160 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// AliasFile maps the friendly names given with SetAlias to versions
+const AliasFile = "aliases.json"
+
+// ErrNoAlias is returned by RemoveAlias for a name that isn't an alias
+var ErrNoAlias = errors.New("no such alias")
+
+// aliasName matches the names an alias can have, such as prod or team.staging
+var aliasName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_.-]*$`)
+
+// versionLike matches names that read as a version even when they aren't a full one, such as v1
+var versionLike = regexp.MustCompile(`^v[0-9]`)
+
+// ValidateAlias - checks name can be an alias without being mistaken for a
+// version, a keyword such as latest or a version range
+func ValidateAlias(name string) error {
+	switch {
+	case !aliasName.MatchString(name):
+		return fmt.Errorf("invalid alias %q, expected a letter followed by letters, digits, '.', '-' or '_'", name)
+	case name == "latest", name == "stable", strings.HasPrefix(name, "latest-"), versionLike.MatchString(name):
+		return fmt.Errorf("invalid alias %q, it would be taken for a version", name)
+	}
+
+	return nil
+}
+
+// Aliases - returns the aliases set with SetAlias, mapping each name to its
+// version. The versions don't have to be installed.
+func (m *Manager) Aliases() (map[string]string, error) {
+	aliases := map[string]string{}
+
+	path := filepath.Join(m.opts.Home, AliasFile)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return aliases, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Unlike the manifest, aliases can't be rebuilt so a corrupt file is an error
+	if err := json.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("unable to read %s: %w", path, err)
+	}
+	if aliases == nil {
+		aliases = map[string]string{}
+	}
+
+	return aliases, nil
+}
+
+// SetAlias - makes name refer to version, replacing whatever it referred to
+// before. Aliases are stored by version so they outlast a reinstall.
+func (m *Manager) SetAlias(name, version string) error {
+	if err := ValidateAlias(name); err != nil {
+		return err
+	}
+
+	version = NormalizeVersion(version)
+	if err := ValidateVersion(version); err != nil {
+		return err
+	}
+
+	aliases, err := m.Aliases()
+	if err != nil {
+		return err
+	}
+	aliases[name] = version
+
+	return m.writeAliases(aliases)
+}
+
+// RemoveAlias - removes name, returning the version it referred to
+func (m *Manager) RemoveAlias(name string) (string, error) {
+	aliases, err := m.Aliases()
+	if err != nil {
+		return "", err
+	}
+
+	version, ok := aliases[name]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrNoAlias, name)
+	}
+	delete(aliases, name)
+
+	return version, m.writeAliases(aliases)
+}
+
+// ExpandAlias - returns the version version refers to when it is an alias,
+// and version itself otherwise
+func (m *Manager) ExpandAlias(version string) (string, error) {
+	if ValidateAlias(version) != nil {
+		return version, nil
+	}
+
+	aliases, err := m.Aliases()
+	if err != nil {
+		return "", err
+	}
+
+	if aliased, ok := aliases[version]; ok {
+		m.debugf("%s is an alias of %s", version, aliased)
+		return aliased, nil
+	}
+
+	return version, nil
+}
+
+// writeAliases - replaces the alias file atomically
+func (m *Manager) writeAliases(aliases map[string]string) error {
+	dir, err := m.Dir()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(aliases, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, AliasFile)
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	return nil
+}
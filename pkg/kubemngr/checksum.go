@@ -0,0 +1,98 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// fetchChecksum - downloads a published .sha256 file and returns the digest it contains
+func (m *Manager) fetchChecksum(url string) (string, error) {
+	m.debugf("Fetching checksum %s", url)
+	res, err := m.HTTPClient().Get(url)
+	if err != nil {
+		return "", m.requestError("unable to fetch checksum", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to fetch checksum: %s returned %s", url, res.Status)
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("unable to read checksum: %v", err)
+	}
+
+	// The file may be a bare digest or "<digest>  <filename>"
+	fields := strings.Fields(string(body))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("checksum file %s is empty", url)
+	}
+
+	return strings.ToLower(fields[0]), nil
+}
+
+// fileChecksum - computes the hex encoded SHA256 digest of a file on disk
+func fileChecksum(path string) (string, error) {
+	digest, err := fileDigest(path)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(digest), nil
+}
+
+// fileDigest - computes the SHA256 digest of a file on disk
+func fileDigest(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return nil, err
+	}
+
+	return hash.Sum(nil), nil
+}
+
+// verifyChecksum - compares the SHA256 digest of the file at path against the one published at url
+func (m *Manager) verifyChecksum(path string, digest []byte, url string) error {
+	expected, err := m.fetchChecksum(url)
+	if err != nil {
+		return err
+	}
+
+	actual := hex.EncodeToString(digest)
+	m.debugf("Checksum of %s: expected %s, got %s", path, expected, actual)
+
+	if actual != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", path, expected, actual)
+	}
+
+	return nil
+}
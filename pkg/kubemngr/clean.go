@@ -0,0 +1,75 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// BrokenFile is a leftover or invalid file found by Broken or Garbage
+type BrokenFile struct {
+	Path   string
+	Size   int64
+	Reason string
+}
+
+// Broken - finds partial downloads and binaries that are empty or aren't
+// executables, such as those left behind by interrupted older releases
+func (m *Manager) Broken() ([]BrokenFile, error) {
+	files, err := ioutil.ReadDir(m.opts.Home)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var broken []BrokenFile
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || binaryOfName(name) == "" {
+			continue
+		}
+
+		path := filepath.Join(m.opts.Home, name)
+		reason := ""
+		switch {
+		case strings.HasSuffix(name, ".download"):
+			reason = "partial download"
+		case file.Size() == 0:
+			reason = "empty file"
+		default:
+			goos := runtime.GOOS
+			if sys, _, ok := crossBinaryPlatform(name); ok {
+				goos = sys
+			}
+			if validateExecutable(path, goos) != nil {
+				reason = "not a " + goos + " executable"
+			}
+		}
+
+		if reason != "" {
+			broken = append(broken, BrokenFile{Path: path, Size: file.Size(), Reason: reason})
+		}
+	}
+
+	return broken, nil
+}
@@ -0,0 +1,195 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/go-version"
+)
+
+// EOLURL lists the Kubernetes minor versions with their end of support dates
+const EOLURL = "https://endoflife.date/api/kubernetes.json"
+
+const (
+	// EOLFile is the cached end of life data in Options.CacheDir
+	EOLFile = "kubernetes-eol.json"
+
+	// EOLTTL is how long the cached end of life data is used before it is fetched again
+	EOLTTL = 24 * time.Hour
+)
+
+// EOLCycle is a Kubernetes minor version and when it stops getting fixes
+type EOLCycle struct {
+	// Minor is the release cycle, such as 1.21
+	Minor string `json:"minor"`
+
+	// EOL is the end of support date, zero when it hasn't been announced
+	EOL time.Time `json:"eol"`
+}
+
+// Past - reports whether the cycle reached its end of life before now
+func (c *EOLCycle) Past(now time.Time) bool {
+	return !c.EOL.IsZero() && now.After(c.EOL)
+}
+
+// eolIndex is the end of life data as cached on disk
+type eolIndex struct {
+	Fetched time.Time  `json:"fetched"`
+	Cycles  []EOLCycle `json:"cycles"`
+}
+
+// EOL - returns the release cycle of version with its end of life date, or
+// nil when the cycle isn't known. The data is cached in Options.CacheDir for
+// EOLTTL and the cache is used when the remote can't be reached.
+func (m *Manager) EOL(v string) (*EOLCycle, error) {
+	parsed, err := version.NewVersion(v)
+	if err != nil {
+		return nil, err
+	}
+	segments := parsed.Segments()
+	minor := fmt.Sprintf("%d.%d", segments[0], segments[1])
+
+	cycles, err := m.eolCycles()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cycle := range cycles {
+		if cycle.Minor == minor {
+			return &cycle, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// eolCycles - returns the end of life data, from the cache while it is fresh
+func (m *Manager) eolCycles() ([]EOLCycle, error) {
+	cached, cacheErr := m.readEOL()
+	if m.opts.Offline {
+		if cacheErr != nil {
+			return nil, fmt.Errorf("%w: no cached end of life data", ErrOffline)
+		}
+		return cached.Cycles, nil
+	}
+	if cacheErr == nil && time.Since(cached.Fetched) < EOLTTL {
+		return cached.Cycles, nil
+	}
+
+	cycles, err := m.fetchEOL()
+	if err != nil {
+		if cacheErr != nil {
+			return nil, err
+		}
+
+		m.debugf("%v. Using the end of life data cached at %s", err, cached.Fetched.Format(time.RFC3339))
+		return cached.Cycles, nil
+	}
+
+	if err := m.writeEOL(cycles); err != nil {
+		m.debugf("Unable to cache the end of life data: %v", err)
+	}
+
+	return cycles, nil
+}
+
+// readEOL - reads the cached end of life data, an error when there is none
+func (m *Manager) readEOL() (*eolIndex, error) {
+	if m.opts.CacheDir == "" {
+		return nil, os.ErrNotExist
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(m.opts.CacheDir, EOLFile))
+	if err != nil {
+		return nil, err
+	}
+
+	index := &eolIndex{}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+// writeEOL - caches cycles in Options.CacheDir, doing nothing without one
+func (m *Manager) writeEOL(cycles []EOLCycle) error {
+	if m.opts.CacheDir == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(eolIndex{Fetched: time.Now(), Cycles: cycles})
+	if err != nil {
+		return err
+	}
+
+	if err := EnsureDir(m.opts.CacheDir); err != nil {
+		return err
+	}
+
+	path := filepath.Join(m.opts.CacheDir, EOLFile)
+	if err := ioutil.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// fetchEOL - downloads the end of life data from endoflife.date
+func (m *Manager) fetchEOL() ([]EOLCycle, error) {
+	m.debugf("Fetching %s", EOLURL)
+	res, err := m.HTTPClient().Get(EOLURL)
+	if err != nil {
+		return nil, m.requestError("unable to fetch end of life data", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unable to fetch end of life data: %s returned %s", EOLURL, res.Status)
+	}
+
+	// eol is a date, or false when it hasn't been announced
+	var releases []struct {
+		Cycle string          `json:"cycle"`
+		EOL   json.RawMessage `json:"eol"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&releases); err != nil {
+		return nil, fmt.Errorf("unable to parse end of life data: %v", err)
+	}
+
+	cycles := make([]EOLCycle, 0, len(releases))
+	for _, release := range releases {
+		cycle := EOLCycle{Minor: release.Cycle}
+
+		var date string
+		if json.Unmarshal(release.EOL, &date) == nil {
+			if cycle.EOL, err = time.Parse("2006-01-02", date); err != nil {
+				return nil, fmt.Errorf("unable to parse end of life data: %v", err)
+			}
+		}
+		cycles = append(cycles, cycle)
+	}
+
+	return cycles, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Garbage - finds files in the kubemngr directory that nothing refers to:
+// temporary files, symlinks to binaries that are gone and kubectl files named
+// the way older releases did. Installed binaries, files in the manifest and
+// directories are never included. Files that are merely broken are left to Broken.
+func (m *Manager) Garbage() ([]BrokenFile, error) {
+	manifest := m.readManifest()
+	garbage, err := m.garbageIn(m.opts.Home, func(name string, file os.FileInfo) string {
+		return m.garbageReason(name, file, manifest)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Only leftovers of switching versions, the link itself belongs to repair
+	binGarbage, err := m.garbageIn(BinDir(m.opts.Home), func(name string, file os.FileInfo) string {
+		if strings.HasSuffix(name, ".tmp") {
+			return "temporary file"
+		}
+		return ""
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return append(garbage, binGarbage...), nil
+}
+
+// garbageIn - lists the files in dir that reason gives a reason to remove
+func (m *Manager) garbageIn(dir string, reason func(string, os.FileInfo) string) ([]BrokenFile, error) {
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var garbage []BrokenFile
+	for _, file := range files {
+		if why := reason(file.Name(), file); why != "" {
+			garbage = append(garbage, BrokenFile{
+				Path:   filepath.Join(dir, file.Name()),
+				Size:   file.Size(),
+				Reason: why,
+			})
+		}
+	}
+
+	return garbage, nil
+}
+
+// garbageReason - says why a file in Home should go, or returns an empty string
+func (m *Manager) garbageReason(name string, file os.FileInfo, manifest Manifest) string {
+	if _, ok := manifest[name]; ok {
+		return ""
+	}
+
+	switch {
+	case file.IsDir(), name == ManifestFile, name == AliasFile, name == ".lock":
+		return ""
+	case file.Mode()&os.ModeSymlink != 0:
+		if _, err := os.Stat(filepath.Join(m.opts.Home, name)); err != nil {
+			return "orphaned symlink"
+		}
+		return "unrecognized symlink"
+	case strings.HasSuffix(name, ".tmp"), strings.HasPrefix(name, ".doctor"):
+		return "temporary file"
+	case strings.HasSuffix(name, ".download"):
+		// A partial download can still be resumed, clean removes it
+		return ""
+	case isBinaryName(name):
+		return ""
+	case strings.HasPrefix(name, "kubectl"):
+		return "old-format name"
+	}
+
+	return "unrecognized file"
+}
+
+// isBinaryName - reports whether name is a binary stored by BinaryPathFor,
+// BinaryPath or CrossBinaryPath, with the v prefix current releases use
+func isBinaryName(name string) bool {
+	version, _, _ := platformFromBinaryName(name)
+	binary := binaryOfName(name)
+	return binary != "" && strings.HasPrefix(name, binary+"-v") && ValidateVersion(version) == nil
+}
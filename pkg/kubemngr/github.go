@@ -0,0 +1,76 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// gitHubAPIHost is the only host Options.GitHubToken is sent to
+const gitHubAPIHost = "api.github.com"
+
+// RateLimitError is returned when the GitHub API refuses a request because
+// the hourly rate limit is used up
+type RateLimitError struct {
+	// Reset is when the limit resets, zero when GitHub didn't say
+	Reset time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	msg := "GitHub API rate limit exceeded"
+	if !e.Reset.IsZero() {
+		msg += ", it resets at " + e.Reset.Local().Format("15:04")
+	}
+
+	return msg + ". Set GITHUB_TOKEN to raise the limit"
+}
+
+// GitHubResponseError - returns the error for a failed GitHub API response,
+// a *RateLimitError when the rate limit is the reason
+func GitHubResponseError(action, url string, res *http.Response) error {
+	limited := res.StatusCode == http.StatusTooManyRequests ||
+		(res.StatusCode == http.StatusForbidden && res.Header.Get("X-RateLimit-Remaining") == "0")
+	if !limited {
+		return fmt.Errorf("%s: %s returned %s", action, url, res.Status)
+	}
+
+	err := &RateLimitError{}
+	if reset, parseErr := strconv.ParseInt(res.Header.Get("X-RateLimit-Reset"), 10, 64); parseErr == nil {
+		err.Reset = time.Unix(reset, 0)
+	}
+
+	return err
+}
+
+// gitHubTokenTransport authenticates requests to the GitHub API. Other hosts,
+// such as the release bucket or a redirect target, never see the token.
+type gitHubTokenTransport struct {
+	token string
+	base  http.RoundTripper
+}
+
+func (t gitHubTokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.token != "" && req.URL.Scheme == "https" && req.URL.Host == gitHubAPIHost {
+		req = req.Clone(req.Context())
+		req.Header.Set("Authorization", "Bearer "+t.token)
+	}
+
+	return t.base.RoundTrip(req)
+}
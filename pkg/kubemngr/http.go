@@ -0,0 +1,163 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	getter "github.com/hashicorp/go-getter"
+)
+
+// HTTPClient - returns the client every metadata request should go through,
+// using Options.Timeout, Options.Proxy and the TLS options
+func (m *Manager) HTTPClient() *http.Client {
+	return m.httpClient(m.opts.Timeout)
+}
+
+// httpClient - returns a client using the configured proxy. A zero timeout means no timeout.
+func (m *Manager) httpClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = m.proxyFunc
+	if m.opts.RootCAs != nil || m.opts.InsecureSkipTLSVerify {
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:            m.opts.RootCAs,
+			InsecureSkipVerify: m.opts.InsecureSkipTLSVerify,
+		}
+	}
+
+	var base http.RoundTripper = gitHubTokenTransport{token: m.opts.GitHubToken, base: transport}
+	if m.opts.Offline {
+		base = offlineTransport{}
+	}
+
+	return &http.Client{
+		Transport:     userAgentTransport{agent: m.opts.UserAgent, base: base},
+		Timeout:       timeout,
+		CheckRedirect: checkRedirect,
+	}
+}
+
+// maxRedirects matches the limit of the default http.Client
+const maxRedirects = 10
+
+// checkRedirect - follows redirects such as dl.k8s.io to the CDN serving the
+// release, but never from https to plain http
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if via[0].URL.Scheme == "https" && req.URL.Scheme != "https" {
+		return fmt.Errorf("refusing to follow redirect from %s to insecure %s", via[0].URL.Host, req.URL)
+	}
+
+	return nil
+}
+
+// proxyFunc - uses Options.Proxy when set, otherwise HTTP(S)_PROXY and NO_PROXY
+func (m *Manager) proxyFunc(req *http.Request) (*url.URL, error) {
+	if m.opts.Proxy != nil {
+		return m.opts.Proxy, nil
+	}
+
+	return http.ProxyFromEnvironment(req)
+}
+
+// requestError - wraps a failed request, calling out timeouts explicitly
+func (m *Manager) requestError(action string, err error) error {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return fmt.Errorf("%s: timed out after %s", action, m.opts.Timeout)
+	}
+
+	return fmt.Errorf("%s: %w", action, err)
+}
+
+// httpGetters - go-getter getters that download through httpClient. go-getter
+// doesn't attach its context to requests, so ctx is bound in the transport to
+// make cancelling it abort a stalled read.
+func (m *Manager) httpGetters(ctx context.Context) map[string]getter.Getter {
+	client := m.httpClient(0)
+	client.Transport = contextTransport{ctx: ctx, base: client.Transport}
+
+	httpGetter := &getter.HttpGetter{
+		Client: client,
+		Netrc:  true,
+	}
+
+	return map[string]getter.Getter{
+		"http":  httpGetter,
+		"https": httpGetter,
+	}
+}
+
+// contextTransport sends every request with ctx
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
+
+// offlineTransport fails every request straight away with ErrOffline
+type offlineTransport struct{}
+
+func (offlineTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, ErrOffline
+}
+
+// userAgentTransport sets the User-Agent on requests that don't have one, so
+// metadata requests and go-getter downloads identify themselves the same way
+type userAgentTransport struct {
+	agent string
+	base  http.RoundTripper
+}
+
+func (t userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.agent != "" && req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.agent)
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// logProxy - logs the proxy requests to rawURL will use at debug level
+func (m *Manager) logProxy(rawURL string) {
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return
+	}
+
+	p, err := m.proxyFunc(req)
+	switch {
+	case err != nil:
+		m.debugf("Unable to determine proxy: %v", err)
+	case p == nil:
+		m.debugf("Not using a proxy")
+	default:
+		m.debugf("Using proxy %s://%s", p.Scheme, p.Host)
+	}
+}
@@ -0,0 +1,644 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	getter "github.com/hashicorp/go-getter"
+)
+
+// ErrNoDarwinArm64Build is returned by Install when a version has no darwin/arm64
+// build and InstallOptions.Rosetta is not set
+var ErrNoDarwinArm64Build = errors.New("no darwin/arm64 build")
+
+// InstallOptions control a single Install
+type InstallOptions struct {
+	// Version is a release, latest, latest-<major>.<minor> or a semver range
+	Version string
+
+	// Binary is the release binary to install, one of Binaries. DefaultBinary when empty.
+	Binary string
+
+	// From installs a kubectl binary already on disk instead of downloading
+	// one, Version must then be a release
+	From string
+
+	// Force replaces the version if it is already installed
+	Force bool
+
+	// DryRun resolves the version and works out where it would come from and
+	// be installed to without downloading or writing anything
+	DryRun bool
+
+	// SkipChecksum skips verifying the published SHA256 checksum
+	SkipChecksum bool
+
+	// SignatureKey is the path of a PEM public key or CA certificates the cosign
+	// signature is verified against. A signing certificate must have been
+	// issued to SigningIdentity. The signature isn't checked when empty.
+	SignatureKey string
+
+	// Rosetta installs the darwin/amd64 build when a version has no darwin/arm64 build
+	Rosetta bool
+
+	// Retries is the number of download attempts, at least one is always made
+	Retries int
+
+	// DownloadTimeout bounds the download, zero means no limit
+	DownloadTimeout time.Duration
+
+	// VerifyExec runs the downloaded kubectl to check it works. When nil the
+	// check only runs for builds native to this machine.
+	VerifyExec *bool
+
+	// OS and Arch download the build for another platform, such as linux and
+	// arm64, instead of the one for this machine
+	OS   string
+	Arch string
+
+	// Progress tracks the download, nothing is shown when nil
+	Progress getter.ProgressTracker
+}
+
+// Installation describes a completed Install
+type Installation struct {
+	Version string
+	Path    string
+
+	// OS and Arch are the platform of the installed build
+	OS   string
+	Arch string
+
+	// Source is the URL or file the binary was installed from
+	Source string
+
+	// SHA256 is the checksum of the installed binary, empty for a dry run
+	SHA256 string
+
+	// Verified is set when SHA256 matched the checksum published with the release
+	Verified bool
+
+	// Replaced is set when an existing install of the version was overwritten
+	Replaced bool
+}
+
+// Install - downloads, verifies and installs a kubectl version, or a version
+// of InstallOptions.Binary. Cancelling ctx stops the download and removes
+// whatever was written.
+func (m *Manager) Install(ctx context.Context, opts InstallOptions) (*Installation, error) {
+	binary := opts.binary()
+	if err := ValidateBinary(binary); err != nil {
+		return nil, err
+	}
+	// Only kubectl builds are kept for several platforms side by side
+	if binary != DefaultBinary && (opts.OS != "" || opts.Arch != "") {
+		return nil, fmt.Errorf("builds for other platforms can only be installed for kubectl, not %s", binary)
+	}
+
+	version := opts.Version
+	if len(version) == 0 {
+		return nil, fmt.Errorf("no %s version specified", binary)
+	}
+
+	// A local file can't be matched against keywords or ranges
+	if opts.From != "" && !releaseTag.MatchString(version) {
+		return nil, fmt.Errorf("installing from a file needs a release version such as v1.21.0, not %q", version)
+	}
+	// Without TLS verification the checksum is all that shows the download wasn't tampered with
+	if opts.From == "" && opts.SkipChecksum && m.opts.InsecureSkipTLSVerify {
+		return nil, errors.New("checksums can't be skipped while TLS verification is disabled")
+	}
+	if opts.From == "" {
+		resolved, err := m.ResolveRelease(version)
+		if err != nil {
+			return nil, err
+		}
+		if resolved != NormalizeVersion(version) {
+			m.infof("Resolved %s to %s", version, resolved)
+		}
+		version = resolved
+	}
+	version = NormalizeVersion(version)
+
+	if err := ValidateVersion(version); err != nil {
+		return nil, err
+	}
+
+	// A dry run mustn't create anything, not even the directory
+	dir := m.opts.Home
+	if !opts.DryRun {
+		if _, err := m.Dir(); err != nil {
+			return nil, fmt.Errorf("unable to prepare kubemngr directory: %w", err)
+		}
+	}
+	sys, machine, err := hostPlatform()
+	if err != nil {
+		return nil, err
+	}
+
+	// Builds for other platforms are kept apart from the ones that can be used here
+	kubectl := BinaryPathFor(dir, binary, version)
+	if opts.OS != "" || opts.Arch != "" {
+		hostSys, hostMachine := sys, machine
+		if sys, machine, err = targetPlatform(sys, machine, opts.OS, opts.Arch); err != nil {
+			return nil, err
+		}
+		if sys != hostSys || machine != hostMachine {
+			kubectl = CrossBinaryPath(dir, version, sys, machine)
+		}
+	}
+
+	// Check if current version already exists. With Force the existing binary
+	// stays in place until the new one has been verified and renamed over it.
+	_, err = os.Stat(kubectl)
+	reinstall := err == nil
+	if reinstall && !opts.Force {
+		if kubectl != BinaryPathFor(dir, binary, version) {
+			return nil, fmt.Errorf("%s for %s/%s is %w", version, sys, machine, ErrAlreadyInstalled)
+		}
+		if binary != DefaultBinary {
+			return nil, fmt.Errorf("%s %s is %w", binary, version, ErrAlreadyInstalled)
+		}
+		return nil, fmt.Errorf("%s is %w", version, ErrAlreadyInstalled)
+	}
+
+	if opts.DryRun {
+		source := opts.From
+		if source == "" {
+			base, err := m.BaseURL()
+			if err != nil {
+				return nil, err
+			}
+			source = binaryURL(base, binary, version, sys, machine)
+		}
+
+		return &Installation{
+			Version:  version,
+			Path:     kubectl,
+			OS:       sys,
+			Arch:     machine,
+			Source:   source,
+			Replaced: reinstall,
+		}, nil
+	}
+
+	if opts.From == "" && m.opts.Offline {
+		return nil, fmt.Errorf("%w: unable to download %s %s, install it from a local file instead", ErrOffline, binary, version)
+	}
+
+	// Download next to the final location so the rename below stays on the
+	// same filesystem, and only move it into place once it has been validated.
+	tmpFile := kubectl + ".download"
+
+	// Unlike a timeout, an interrupted install was stopped on purpose so don't
+	// keep anything around. Removing the file is harmless once it has been renamed.
+	finished := make(chan struct{})
+	defer close(finished)
+	go func() {
+		select {
+		case <-ctx.Done():
+			os.Remove(tmpFile)
+		case <-finished:
+		}
+	}()
+
+	source := opts.From
+	var digest []byte
+	if opts.From != "" {
+		info, err := os.Stat(opts.From)
+		if err != nil {
+			return nil, fmt.Errorf("unable to copy %s: %w", opts.From, err)
+		}
+		if err := checkFreeSpace(dir, info.Size()); err != nil {
+			return nil, fmt.Errorf("unable to copy %s: %w", opts.From, err)
+		}
+
+		m.infof("Copying %s", opts.From)
+		if digest, err = copyFile(opts.From, tmpFile); err != nil {
+			os.Remove(tmpFile)
+			return nil, fmt.Errorf("unable to copy %s: %w", opts.From, err)
+		}
+	} else if source, machine, digest, err = m.download(ctx, opts, version, sys, machine, tmpFile); err != nil {
+		return nil, err
+	}
+
+	// Make sure we got an ELF/Mach-O binary rather than an error page
+	if err := validateExecutable(tmpFile, sys); err != nil {
+		os.Remove(tmpFile)
+		if opts.From != "" {
+			return nil, fmt.Errorf("%s is not a %s binary for this machine: %w", opts.From, binary, err)
+		}
+		return nil, fmt.Errorf("the downloaded binary is not in the expected format, please check the version and try again: %w", err)
+	}
+
+	// Set executable permissions on the kubectl binary. Chmod ignores the
+	// umask, so apply it here the way creating the file would have.
+	if err := os.Chmod(tmpFile, m.opts.BinaryMode&^m.umask); err != nil {
+		return nil, fmt.Errorf("unable to make %s %s executable: %w", binary, version, err)
+	}
+
+	// A binary for another OS or arch is only run when asked for explicitly
+	verifyExec := sys == runtime.GOOS && machine == runtime.GOARCH
+	if opts.VerifyExec != nil {
+		verifyExec = *opts.VerifyExec
+	}
+	if _, ok := versionArgs[binary]; verifyExec && ok {
+		out, err := verifyRuns(ctx, binary, tmpFile)
+		if err != nil {
+			os.Remove(tmpFile)
+			return nil, fmt.Errorf("unable to verify %s %s: %w", binary, version, err)
+		}
+		m.infof("Verified %s runs: %s", binary, out)
+	}
+
+	if ctx.Err() != nil {
+		os.Remove(tmpFile)
+		return nil, fmt.Errorf("install of %s %s was interrupted", binary, version)
+	}
+
+	sum := hex.EncodeToString(digest)
+
+	m.debugf("Moving %s to %s", tmpFile, kubectl)
+	if err := os.Rename(tmpFile, kubectl); err != nil {
+		return nil, fmt.Errorf("unable to install %s %s: %w", binary, version, err)
+	}
+
+	// The binary is installed at this point, an outdated manifest is rebuilt later
+	manifestErr := m.updateManifest(func(manifest Manifest) {
+		manifest[filepath.Base(kubectl)] = ManifestEntry{
+			Version:   version,
+			OS:        sys,
+			Arch:      machine,
+			SHA256:    sum,
+			Source:    source,
+			Installed: time.Now().UTC(),
+		}
+	})
+	if manifestErr != nil {
+		m.warnf("unable to update %s: %v", ManifestFile, manifestErr)
+	}
+
+	return &Installation{
+		Version:  version,
+		Path:     kubectl,
+		OS:       sys,
+		Arch:     machine,
+		Source:   source,
+		SHA256:   sum,
+		Verified: opts.From == "" && !opts.SkipChecksum,
+		Replaced: reinstall,
+	}, nil
+}
+
+// download - fetches and verifies the release of version for sys and machine
+// into tmpFile, returning the URL and arch that were actually downloaded and
+// the SHA256 digest of the binary. The file is hashed once, after the last
+// retry, and every check after that uses the digest. A
+// partial download is kept so the next attempt can resume it with a range
+// request, but anything that fails verification is thrown away.
+func (m *Manager) download(ctx context.Context, opts InstallOptions, version, sys, machine, tmpFile string) (string, string, []byte, error) {
+	binary := opts.binary()
+	base, err := m.BaseURL()
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	src := binaryURL(base, binary, version, sys, machine)
+	m.logProxy(src)
+
+	// Make sure the version exists before anything is written to disk
+	found, size, err := m.remoteExists(ctx, src)
+
+	// Without a mirror configured fall back to the legacy bucket when
+	// dl.k8s.io can't be reached
+	if err != nil && ctx.Err() == nil && m.opts.Mirror == "" {
+		m.warnf("unable to reach %s (%v), trying %s", ReleaseURL, err, LegacyReleaseURL)
+		base = LegacyReleaseURL
+		src = binaryURL(base, binary, version, sys, machine)
+		found, size, err = m.remoteExists(ctx, src)
+	}
+	if err != nil {
+		return "", "", nil, m.requestError("unable to check for "+binary+" "+version, err)
+	}
+
+	// darwin/arm64 builds are only published for recent versions, older ones
+	// can still run on Apple Silicon through Rosetta 2.
+	if !found && sys == "darwin" && machine == "arm64" {
+		if !opts.Rosetta {
+			return "", "", nil, fmt.Errorf("%s %s has %w", binary, version, ErrNoDarwinArm64Build)
+		}
+
+		m.warnf("%s %s has no darwin/arm64 build, installing darwin/amd64 which requires Rosetta 2", binary, version)
+		machine = "amd64"
+		src = binaryURL(base, binary, version, sys, machine)
+
+		found, size, err = m.remoteExists(ctx, src)
+		if err != nil {
+			return "", "", nil, m.requestError("unable to check for "+binary+" "+version, err)
+		}
+	}
+
+	if !found {
+		return "", "", nil, fmt.Errorf("%s %s not found at %s, please check the version", binary, version, src)
+	}
+
+	var written int64
+	if info, err := os.Stat(tmpFile); err == nil {
+		written = info.Size()
+	}
+	resumed := written > 0
+
+	needed := size
+	if needed < 0 {
+		needed = defaultDownloadSize
+	}
+	if err := checkFreeSpace(filepath.Dir(tmpFile), needed-written); err != nil {
+		return "", "", nil, fmt.Errorf("unable to download %s %s: %w", binary, version, err)
+	}
+
+	downloadCtx, cancel := context.WithCancel(ctx)
+	if opts.DownloadTimeout > 0 {
+		downloadCtx, cancel = context.WithTimeout(ctx, opts.DownloadTimeout)
+	}
+	defer cancel()
+
+	client := getter.Client{
+		Ctx:              downloadCtx,
+		Src:              src,
+		Dst:              tmpFile,
+		Getters:          m.httpGetters(downloadCtx),
+		ProgressListener: opts.Progress,
+	}
+	m.debugf("Downloading to %s", tmpFile)
+	if resumed {
+		m.infof("Resuming %v", client.Src)
+	} else {
+		m.infof("Downloading %v", client.Src)
+	}
+	err = m.getWithRetry(&client, opts.Retries)
+	if ctx.Err() != nil {
+		os.Remove(tmpFile)
+		return "", "", nil, fmt.Errorf("download of %s %s was interrupted", binary, version)
+	}
+	if downloadCtx.Err() == context.DeadlineExceeded {
+		return "", "", nil, fmt.Errorf("download of %s %s timed out after %s. Run install again to resume", binary, version, opts.DownloadTimeout)
+	}
+	if err != nil {
+		return "", "", nil, fmt.Errorf("unable to download %s %s, run install again to resume: %w", binary, version, err)
+	}
+
+	// A dropped connection can leave a truncated file that still looks like a
+	// binary. A server that ignores the range request appends the whole
+	// binary to the partial one instead, so start again from scratch then.
+	err = verifySize(tmpFile, size)
+	if err != nil && resumed {
+		m.infof("Resumed download is the wrong size, downloading again")
+		os.Remove(tmpFile)
+		resumed = false
+		if err = m.getWithRetry(&client, opts.Retries); err == nil {
+			err = verifySize(tmpFile, size)
+		}
+	}
+	if err != nil {
+		os.Remove(tmpFile)
+		return "", "", nil, fmt.Errorf("unable to download %s %s: %w", binary, version, err)
+	}
+
+	digest, err := fileDigest(tmpFile)
+	if err != nil {
+		os.Remove(tmpFile)
+		return "", "", nil, fmt.Errorf("unable to verify %s %s: %w", binary, version, err)
+	}
+
+	if !opts.SkipChecksum {
+		err := m.verifyChecksum(tmpFile, digest, client.Src+".sha256")
+
+		// A server that ignores the range request appends the whole binary
+		// to the partial one, so start again from scratch
+		if err != nil && resumed {
+			m.infof("Resumed download failed verification, downloading again")
+			os.Remove(tmpFile)
+			if err = m.getWithRetry(&client, opts.Retries); err == nil {
+				if digest, err = fileDigest(tmpFile); err == nil {
+					err = m.verifyChecksum(tmpFile, digest, client.Src+".sha256")
+				}
+			}
+		}
+
+		if err != nil {
+			os.Remove(tmpFile)
+			return "", "", nil, fmt.Errorf("unable to verify %s %s: %w", binary, version, err)
+		}
+	}
+
+	if opts.SignatureKey != "" {
+		if err := m.verifySignature(tmpFile, digest, client.Src, opts.SignatureKey); err != nil {
+			os.Remove(tmpFile)
+			return "", "", nil, fmt.Errorf("unable to verify %s %s: %w", binary, version, err)
+		}
+	}
+
+	return src, machine, digest, nil
+}
+
+// binary - returns the binary to install, DefaultBinary unless Binary is set
+func (opts InstallOptions) binary() string {
+	if opts.Binary == "" {
+		return DefaultBinary
+	}
+
+	return opts.Binary
+}
+
+// binaryURL - builds the URL of a release binary for a version and platform under base
+func binaryURL(base, binary, version, sys, machine string) string {
+	if sys == "windows" {
+		binary += ".exe"
+	}
+
+	return fmt.Sprintf("%s/%s/bin/%s/%s/%s", base, version, sys, machine, binary)
+}
+
+// remoteExists - reports whether url can be downloaded and its size, -1 when
+// the server doesn't say. A 404 counts as missing and any other non-200
+// status as an error.
+func (m *Manager) remoteExists(ctx context.Context, url string) (bool, int64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return false, -1, err
+	}
+
+	res, err := m.HTTPClient().Do(req)
+	if err != nil {
+		return false, -1, err
+	}
+	res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return true, res.ContentLength, nil
+	case http.StatusNotFound:
+		return false, -1, nil
+	}
+
+	return false, -1, fmt.Errorf("%s returned %s", url, res.Status)
+}
+
+// defaultDownloadSize is assumed when a server doesn't report the size of a
+// kubectl binary, comfortably above any release so far
+const defaultDownloadSize = 100 << 20
+
+// checkFreeSpace - fails when the filesystem holding dir has less than needed
+// bytes free, so a full disk is reported up front rather than as a write error
+// halfway through. Platforms where free space can't be read aren't checked.
+func checkFreeSpace(dir string, needed int64) error {
+	if needed <= 0 {
+		return nil
+	}
+
+	free, err := freeSpace(dir)
+	if err != nil {
+		return nil
+	}
+	if free < uint64(needed) {
+		return fmt.Errorf("not enough disk space in %s, %s needed but only %s free", dir, formatSize(needed), formatSize(int64(free)))
+	}
+
+	return nil
+}
+
+// formatSize - formats a byte count in MiB for messages
+func formatSize(n int64) string {
+	return fmt.Sprintf("%.1f MiB", float64(n)/(1<<20))
+}
+
+// verifySize - flushes path to disk and checks it is size bytes long. The
+// size check is skipped when size is unknown.
+func verifySize(path string, size int64) error {
+	if err := syncFile(path); err != nil {
+		return err
+	}
+	if size < 0 {
+		return nil
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.Size() != size {
+		return fmt.Errorf("download is incomplete, got %d of %d bytes", info.Size(), size)
+	}
+
+	return nil
+}
+
+// releaseArches maps uname -m values to the arch directories in the release bucket
+var releaseArches = map[string]string{
+	"x86_64":  "amd64",
+	"amd64":   "amd64",
+	"arm64":   "arm64",
+	"aarch64": "arm64",
+	"arm":     "arm",
+	"armv7l":  "arm",
+	"ppc64le": "ppc64le",
+	"s390x":   "s390x",
+	"386":     "386",
+	"i386":    "386",
+	"i686":    "386",
+}
+
+// hostPlatform - returns the OS and arch of this machine as named in the release bucket
+func hostPlatform() (string, string, error) {
+	uname, err := getOSInfo()
+	if err != nil {
+		return "", "", err
+	}
+
+	// Compare system name to set value for building url to download kubectl binary
+	if uname.Sysname != "Linux" && uname.Sysname != "Darwin" && uname.Sysname != "Windows" {
+		return "", "", fmt.Errorf("unsupported OS: %s, check github.com/zee-ahmed/kubemngr for issues", uname.Sysname)
+	}
+	machine, ok := releaseArches[uname.Machine]
+	if !ok {
+		return "", "", fmt.Errorf("unsupported arch: %s, supported arches are %s", uname.Machine, supportedArches())
+	}
+
+	return strings.ToLower(uname.Sysname), machine, nil
+}
+
+// releasePlatforms lists the arches kubectl is published for on each OS
+var releasePlatforms = map[string][]string{
+	"linux":   {"amd64", "arm64", "arm", "ppc64le", "s390x", "386"},
+	"darwin":  {"amd64", "arm64"},
+	"windows": {"amd64", "arm64", "386"},
+}
+
+// targetPlatform - applies the os and arch overrides to the detected platform,
+// accepting uname style arches such as x86_64, and checks kubectl is
+// published for the result
+func targetPlatform(sys, machine, overrideOS, overrideArch string) (string, string, error) {
+	if overrideOS != "" {
+		sys = strings.ToLower(overrideOS)
+	}
+	if overrideArch != "" {
+		arch, ok := releaseArches[strings.ToLower(overrideArch)]
+		if !ok {
+			return "", "", fmt.Errorf("unsupported arch: %s, supported arches are %s", overrideArch, supportedArches())
+		}
+		machine = arch
+	}
+
+	arches, ok := releasePlatforms[sys]
+	if !ok {
+		return "", "", fmt.Errorf("unsupported OS: %s, supported are darwin, linux and windows", sys)
+	}
+	for _, arch := range arches {
+		if arch == machine {
+			return sys, machine, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("kubectl isn't published for %s/%s, %s builds are %s", sys, machine, sys, strings.Join(arches, ", "))
+}
+
+// supportedArches - lists the keys of releaseArches for error messages
+func supportedArches() string {
+	arches := make([]string, 0, len(releaseArches))
+	for arch := range releaseArches {
+		arches = append(arches, arch)
+	}
+	sort.Strings(arches)
+
+	return strings.Join(arches, ", ")
+}
+
+type uname struct {
+	Sysname string
+	Machine string
+}
@@ -0,0 +1,281 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeELF passes the executable check for linux builds
+var fakeELF = append([]byte("\x7fELF"), make([]byte, 1024)...)
+
+// fakeMachO passes the executable check for darwin builds
+var fakeMachO = append([]byte{0xcf, 0xfa, 0xed, 0xfe}, make([]byte, 1024)...)
+
+// releaseServer - serves files as a release mirror, keyed by path such as
+// /v1.21.0/bin/linux/amd64/kubectl. Everything else is a 404.
+func releaseServer(files map[string][]byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeContent(w, r, filepath.Base(r.URL.Path), time.Time{}, bytes.NewReader(body))
+	}))
+}
+
+// checksumOf - returns the contents of a published .sha256 file for body
+func checksumOf(body []byte) []byte {
+	sum := sha256.Sum256(body)
+	return []byte(hex.EncodeToString(sum[:]))
+}
+
+// testManager - returns a Manager storing binaries in a temporary directory
+// and downloading from server, and a func removing the directory
+func testManager(t *testing.T, server *httptest.Server) (*Manager, func()) {
+	t.Helper()
+
+	tmp, err := ioutil.TempDir("", "kubemngr-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := New(Options{Home: filepath.Join(tmp, "kubemngr"), Mirror: server.URL})
+	if err != nil {
+		os.RemoveAll(tmp)
+		t.Fatal(err)
+	}
+
+	return m, func() { os.RemoveAll(tmp) }
+}
+
+// linuxInstall - returns options installing the linux/amd64 build of version
+// without running it, so the tests pass on any machine
+func linuxInstall(version string) InstallOptions {
+	verifyExec := false
+	return InstallOptions{Version: version, OS: "linux", Arch: "amd64", VerifyExec: &verifyExec}
+}
+
+// assertNoDownloads - fails when dir holds anything but the bin directory and manifest
+func assertNoDownloads(t *testing.T, dir string) {
+	t.Helper()
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	for _, file := range files {
+		if name := file.Name(); name != "bin" && name != ManifestFile {
+			t.Errorf("%s was left behind", name)
+		}
+	}
+}
+
+func TestInstallDownloadsAndVerifies(t *testing.T) {
+	server := releaseServer(map[string][]byte{
+		"/v1.21.0/bin/linux/amd64/kubectl":        fakeELF,
+		"/v1.21.0/bin/linux/amd64/kubectl.sha256": checksumOf(fakeELF),
+	})
+	defer server.Close()
+	m, cleanup := testManager(t, server)
+	defer cleanup()
+
+	installed, err := m.Install(context.Background(), linuxInstall("1.21.0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if installed.Version != "v1.21.0" {
+		t.Errorf("got version %s, want v1.21.0", installed.Version)
+	}
+	if want := server.URL + "/v1.21.0/bin/linux/amd64/kubectl"; installed.Source != want {
+		t.Errorf("got source %s, want %s", installed.Source, want)
+	}
+	if !installed.Verified {
+		t.Error("the install wasn't marked as verified")
+	}
+
+	data, err := ioutil.ReadFile(installed.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(fakeELF) {
+		t.Error("the installed binary differs from the one served")
+	}
+	if installed.SHA256 != string(checksumOf(fakeELF)) {
+		t.Errorf("got checksum %s, want %s", installed.SHA256, checksumOf(fakeELF))
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(installed.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode()&0100 == 0 {
+			t.Errorf("the installed binary isn't executable, mode %s", info.Mode())
+		}
+	}
+
+	if _, err := os.Stat(installed.Path + ".download"); !os.IsNotExist(err) {
+		t.Error("the partial download was left behind")
+	}
+}
+
+func TestInstallMissingVersion(t *testing.T) {
+	server := releaseServer(map[string][]byte{})
+	defer server.Close()
+	m, cleanup := testManager(t, server)
+	defer cleanup()
+
+	_, err := m.Install(context.Background(), linuxInstall("1.21.0"))
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("got error %v, want not found", err)
+	}
+
+	assertNoDownloads(t, m.opts.Home)
+}
+
+func TestInstallChecksumMismatch(t *testing.T) {
+	server := releaseServer(map[string][]byte{
+		"/v1.21.0/bin/linux/amd64/kubectl":        fakeELF,
+		"/v1.21.0/bin/linux/amd64/kubectl.sha256": checksumOf([]byte("something else")),
+	})
+	defer server.Close()
+	m, cleanup := testManager(t, server)
+	defer cleanup()
+
+	_, err := m.Install(context.Background(), linuxInstall("1.21.0"))
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("got error %v, want a checksum mismatch", err)
+	}
+
+	assertNoDownloads(t, m.opts.Home)
+}
+
+func TestInstallNotAnExecutable(t *testing.T) {
+	page := []byte("<html>Not Found</html>")
+	server := releaseServer(map[string][]byte{
+		"/v1.21.0/bin/linux/amd64/kubectl":        page,
+		"/v1.21.0/bin/linux/amd64/kubectl.sha256": checksumOf(page),
+	})
+	defer server.Close()
+	m, cleanup := testManager(t, server)
+	defer cleanup()
+
+	_, err := m.Install(context.Background(), linuxInstall("1.21.0"))
+	if err == nil || !strings.Contains(err.Error(), "not in the expected format") {
+		t.Fatalf("got error %v, want the format to be rejected", err)
+	}
+
+	assertNoDownloads(t, m.opts.Home)
+}
+
+func TestInstallAlreadyInstalled(t *testing.T) {
+	server := releaseServer(map[string][]byte{
+		"/v1.21.0/bin/linux/amd64/kubectl":        fakeELF,
+		"/v1.21.0/bin/linux/amd64/kubectl.sha256": checksumOf(fakeELF),
+	})
+	defer server.Close()
+	m, cleanup := testManager(t, server)
+	defer cleanup()
+
+	if _, err := m.Install(context.Background(), linuxInstall("v1.21.0")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := m.Install(context.Background(), linuxInstall("1.21.0"))
+	if !errors.Is(err, ErrAlreadyInstalled) {
+		t.Fatalf("got error %v, want ErrAlreadyInstalled", err)
+	}
+}
+
+func TestBinaryURL(t *testing.T) {
+	tests := []struct {
+		os, arch string
+		want     string
+	}{
+		{"linux", "amd64", "https://dl.k8s.io/release/v1.21.0/bin/linux/amd64/kubectl"},
+		{"linux", "aarch64", "https://dl.k8s.io/release/v1.21.0/bin/linux/arm64/kubectl"},
+		{"darwin", "arm64", "https://dl.k8s.io/release/v1.21.0/bin/darwin/arm64/kubectl"},
+		{"Darwin", "x86_64", "https://dl.k8s.io/release/v1.21.0/bin/darwin/amd64/kubectl"},
+		{"windows", "amd64", "https://dl.k8s.io/release/v1.21.0/bin/windows/amd64/kubectl.exe"},
+	}
+
+	for _, test := range tests {
+		sys, machine, err := targetPlatform("linux", "amd64", test.os, test.arch)
+		if err != nil {
+			t.Errorf("%s/%s: %v", test.os, test.arch, err)
+			continue
+		}
+
+		if got := binaryURL(ReleaseURL, DefaultBinary, "v1.21.0", sys, machine); got != test.want {
+			t.Errorf("%s/%s: got %s, want %s", test.os, test.arch, got, test.want)
+		}
+	}
+}
+
+func TestTargetPlatformUnpublished(t *testing.T) {
+	for _, platform := range [][2]string{{"darwin", "s390x"}, {"freebsd", "amd64"}, {"linux", "mips"}} {
+		if _, _, err := targetPlatform("linux", "amd64", platform[0], platform[1]); err == nil {
+			t.Errorf("%s/%s: got no error", platform[0], platform[1])
+		}
+	}
+}
+
+func TestInstallDarwinArm64Fallback(t *testing.T) {
+	server := releaseServer(map[string][]byte{
+		"/v1.19.0/bin/darwin/amd64/kubectl":        fakeMachO,
+		"/v1.19.0/bin/darwin/amd64/kubectl.sha256": checksumOf(fakeMachO),
+	})
+	defer server.Close()
+	m, cleanup := testManager(t, server)
+	defer cleanup()
+
+	verifyExec := false
+	opts := InstallOptions{Version: "v1.19.0", OS: "darwin", Arch: "arm64", VerifyExec: &verifyExec}
+
+	_, err := m.Install(context.Background(), opts)
+	if !errors.Is(err, ErrNoDarwinArm64Build) {
+		t.Fatalf("got error %v, want ErrNoDarwinArm64Build", err)
+	}
+
+	opts.Rosetta = true
+	installed, err := m.Install(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if installed.Arch != "amd64" {
+		t.Errorf("got arch %s, want amd64", installed.Arch)
+	}
+	if want := server.URL + "/v1.19.0/bin/darwin/amd64/kubectl"; installed.Source != want {
+		t.Errorf("got source %s, want %s", installed.Source, want)
+	}
+}
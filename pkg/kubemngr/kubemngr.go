@@ -0,0 +1,188 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kubemngr installs and switches between kubectl versions. It holds
+// everything the kubemngr command line does apart from parsing flags and
+// printing results, so other Go programs can manage kubectl the same way.
+package kubemngr
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrAlreadyInstalled is returned by Install when the version is already present
+	ErrAlreadyInstalled = errors.New("already installed")
+
+	// ErrNotInstalled is returned when an operation needs a version that isn't installed
+	ErrNotInstalled = errors.New("not installed")
+
+	// ErrOffline is returned for anything that would need the network with Options.Offline
+	ErrOffline = errors.New("offline mode")
+)
+
+// DefaultBinary is the binary kubemngr manages unless told otherwise
+const DefaultBinary = "kubectl"
+
+// ConvertBinary is the kubectl plugin that provides kubectl convert
+const ConvertBinary = "kubectl-convert"
+
+// Binaries are the release binaries kubemngr can install. Only kubectl and
+// kubectl-convert are built for every platform, kubeadm and kubelet are not
+// published for darwin.
+var Binaries = []string{"kubectl", "kubeadm", "kubelet", ConvertBinary}
+
+// ValidateBinary - rejects binaries that aren't in Binaries
+func ValidateBinary(binary string) error {
+	for _, b := range Binaries {
+		if b == binary {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("unknown binary %q, expected one of %s", binary, strings.Join(Binaries, ", "))
+}
+
+const (
+	// DefaultBinaryMode is the mode installed kubectl binaries are given
+	DefaultBinaryMode os.FileMode = 0755
+
+	// DefaultDirMode is the mode of the directories kubemngr creates
+	DefaultDirMode os.FileMode = 0755
+)
+
+// Options configure a Manager. The zero value uses the default locations and
+// the official release bucket.
+type Options struct {
+	// Home is where kubectl binaries are stored, DefaultHome() when empty
+	Home string
+
+	// BinaryMode is given to installed kubectl binaries, less the process
+	// umask. DefaultBinaryMode when zero.
+	BinaryMode os.FileMode
+
+	// DirMode is used to create Home and its bin directory, less the process
+	// umask. DefaultDirMode when zero.
+	DirMode os.FileMode
+
+	// Mirror is the base URL releases are downloaded from, or one of the
+	// presets in Mirrors. ReleaseURL is used when empty.
+	Mirror string
+
+	// CacheDir is where the remote version list is cached, it is fetched
+	// every time when empty
+	CacheDir string
+
+	// IndexTTL is how long the cached version list is used, DefaultIndexTTL when zero
+	IndexTTL time.Duration
+
+	// Timeout applies to metadata requests such as version lists and checksums.
+	// Zero means no timeout.
+	Timeout time.Duration
+
+	// Proxy is used for every request when set, otherwise HTTP_PROXY,
+	// HTTPS_PROXY and NO_PROXY apply
+	Proxy *url.URL
+
+	// RootCAs are the certificate authorities trusted for TLS, the system
+	// pool when nil
+	RootCAs *x509.CertPool
+
+	// InsecureSkipTLSVerify turns off TLS certificate verification, for
+	// proxies that intercept TLS. Checksums can't be skipped with it set.
+	InsecureSkipTLSVerify bool
+
+	// Offline forbids all network access. Versions can only be installed from
+	// local files and the remote version list only comes from the cache.
+	Offline bool
+
+	// Pre lets latest and version ranges resolve to alpha, beta and rc versions
+	Pre bool
+
+	// Logger receives progress messages, nothing is logged when nil
+	Logger Logger
+
+	// GitHubToken authenticates GitHub API requests such as version lists,
+	// raising the rate limit. It is never sent to other hosts.
+	GitHubToken string
+
+	// UserAgent is sent with every request, UserAgent("") when empty
+	UserAgent string
+}
+
+// Manager manages the kubectl versions installed in one directory
+type Manager struct {
+	opts Options
+
+	// resolved caches keyword and range lookups so they only hit the network once
+	resolved     map[string]string
+	resolvedLock sync.Mutex
+
+	// manifestLock serializes updates of the manifest between goroutines, the
+	// file lock only keeps other processes out
+	manifestLock sync.Mutex
+
+	// umask is read once, reading it briefly changes it for the whole process
+	umask os.FileMode
+}
+
+// New - returns a Manager for opts. Nothing is written to disk until it is needed.
+func New(opts Options) (*Manager, error) {
+	if opts.Home == "" {
+		home, err := DefaultHome()
+		if err != nil {
+			return nil, err
+		}
+		opts.Home = home
+	}
+	if opts.BinaryMode == 0 {
+		opts.BinaryMode = DefaultBinaryMode
+	}
+	if opts.DirMode == 0 {
+		opts.DirMode = DefaultDirMode
+	}
+	if opts.IndexTTL == 0 {
+		opts.IndexTTL = DefaultIndexTTL
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = UserAgent("")
+	}
+
+	return &Manager{
+		opts:     opts,
+		resolved: map[string]string{},
+		umask:    umask(),
+	}, nil
+}
+
+// UserAgent - returns the User-Agent kubemngr identifies itself with, such
+// as kubemngr/0.1.2 (linux/amd64). The version is left out when empty.
+func UserAgent(version string) string {
+	product := "kubemngr"
+	if version != "" {
+		product += "/" + version
+	}
+
+	return fmt.Sprintf("%s (%s/%s)", product, runtime.GOOS, runtime.GOARCH)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+)
+
+// InstalledVersion is a kubectl version found in the kubemngr directory
+type InstalledVersion struct {
+	Version  *version.Version
+	Binary   string
+	Path     string
+	Size     int64
+	Modified time.Time
+
+	// OS and Arch are the platform the binary was built for
+	OS   string
+	Arch string
+
+	// SHA256 and Source come from the manifest and are empty for binaries it
+	// doesn't record yet
+	SHA256 string
+	Source string
+}
+
+// List - returns the kubectl versions installed for this machine, oldest first
+func (m *Manager) List() ([]InstalledVersion, error) {
+	return m.list(DefaultBinary, false)
+}
+
+// ListAll - returns every installed kubectl version including builds for
+// other platforms, oldest first
+func (m *Manager) ListAll() ([]InstalledVersion, error) {
+	return m.list(DefaultBinary, true)
+}
+
+// ListBinary - returns the installed versions of binary, such as kubeadm, oldest first
+func (m *Manager) ListBinary(binary string) ([]InstalledVersion, error) {
+	return m.list(binary, false)
+}
+
+// list - lists the installed versions of binary, only those for this machine
+// unless all is set
+func (m *Manager) list(binary string, all bool) ([]InstalledVersion, error) {
+	// Listing is read only, a missing directory just means nothing is installed
+	dir := m.opts.Home
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return []InstalledVersion{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Only read the manifest, rebuilding it here would hash every untracked binary
+	manifest := m.readManifest()
+
+	list := []InstalledVersion{}
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || binaryOfName(name) != binary || strings.HasSuffix(name, ".download") {
+			continue
+		}
+
+		// Builds for other platforms can't be used here
+		if isCrossBinaryName(name) && !all {
+			continue
+		}
+
+		raw, sys, machine := platformFromBinaryName(name)
+		v, err := version.NewVersion(raw)
+		if err != nil {
+			continue
+		}
+
+		entry := manifest[name]
+		list = append(list, InstalledVersion{
+			Version:  v,
+			Binary:   binary,
+			Path:     filepath.Join(dir, name),
+			Size:     file.Size(),
+			Modified: file.ModTime(),
+			OS:       sys,
+			Arch:     machine,
+			SHA256:   entry.SHA256,
+			Source:   entry.Source,
+		})
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		if list[i].Version.Equal(list[j].Version) {
+			return list[i].OS+"/"+list[i].Arch < list[j].OS+"/"+list[j].Arch
+		}
+		return list[i].Version.LessThan(list[j].Version)
+	})
+
+	return list, nil
+}
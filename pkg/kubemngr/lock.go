@@ -0,0 +1,57 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+)
+
+// lockTimeout is how long to wait for another kubemngr process to finish
+const lockTimeout = 30 * time.Second
+
+// errLocked is returned by lockFile when another process holds the lock
+var errLocked = errors.New("locked")
+
+// Lock - takes the lock guarding changes to the kubemngr directory, waiting
+// up to lockTimeout for another kubemngr process to release it. The returned
+// function releases the lock.
+func (m *Manager) Lock() (func(), error) {
+	dir, err := m.Dir()
+	if err != nil {
+		return nil, err
+	}
+	path := filepath.Join(dir, ".lock")
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := lockFile(path)
+		if err == nil {
+			return func() { f.Close() }, nil
+		}
+		if err != errLocked {
+			return nil, fmt.Errorf("unable to lock %s: %w", path, err)
+		}
+		if time.Now().After(deadline) {
+			return nil, errors.New("another kubemngr operation is in progress, try again once it has finished")
+		}
+
+		time.Sleep(100 * time.Millisecond)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"fmt"
+	"io"
+)
+
+// Logger receives the messages a Manager logs while it works. Debug messages
+// add detail such as the proxy in use, temporary paths, retry attempts and
+// checksum comparisons. Messages don't end in a newline.
+type Logger interface {
+	Debugf(format string, args ...interface{})
+	Infof(format string, args ...interface{})
+	Warnf(format string, args ...interface{})
+}
+
+// WriterLogger - returns a Logger writing messages to w, including debug
+// messages when verbose is set
+func WriterLogger(w io.Writer, verbose bool) Logger {
+	return writerLogger{w: w, verbose: verbose}
+}
+
+type writerLogger struct {
+	w       io.Writer
+	verbose bool
+}
+
+func (l writerLogger) Debugf(format string, args ...interface{}) {
+	if l.verbose {
+		fmt.Fprintf(l.w, format+"\n", args...)
+	}
+}
+
+func (l writerLogger) Infof(format string, args ...interface{}) {
+	fmt.Fprintf(l.w, format+"\n", args...)
+}
+
+func (l writerLogger) Warnf(format string, args ...interface{}) {
+	fmt.Fprintf(l.w, "Warning: "+format+"\n", args...)
+}
+
+// debugf, infof and warnf - log through Options.Logger when one is set
+func (m *Manager) debugf(format string, args ...interface{}) {
+	if m.opts.Logger != nil {
+		m.opts.Logger.Debugf(format, args...)
+	}
+}
+
+func (m *Manager) infof(format string, args ...interface{}) {
+	if m.opts.Logger != nil {
+		m.opts.Logger.Infof(format, args...)
+	}
+}
+
+func (m *Manager) warnf(format string, args ...interface{}) {
+	if m.opts.Logger != nil {
+		m.opts.Logger.Warnf(format, args...)
+	}
+}
@@ -0,0 +1,184 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// ManifestFile records how each binary in the kubemngr directory was installed
+const ManifestFile = "installed.json"
+
+// ManifestEntry records how a kubectl binary was installed
+type ManifestEntry struct {
+	Version   string    `json:"version"`
+	OS        string    `json:"os"`
+	Arch      string    `json:"arch"`
+	SHA256    string    `json:"sha256"`
+	Source    string    `json:"source,omitempty"`
+	Installed time.Time `json:"installed"`
+
+	// Rebuilt is set by Manager.Manifest for binaries it found on disk without
+	// an entry. Their SHA256 was computed from the binary as it is now, so it
+	// says nothing about whether it is the one that was installed. Rebuilt
+	// entries are never written to the manifest.
+	Rebuilt bool `json:"-"`
+}
+
+// Manifest maps the kubectl file names in the kubemngr directory to how they
+// were installed
+type Manifest map[string]ManifestEntry
+
+// Manifest - returns the manifest reconciled with the files actually present.
+// Entries for files that are gone are dropped and files the manifest doesn't
+// know about, such as those installed by older releases, are added with a
+// freshly computed checksum and marked Rebuilt. A missing or unreadable
+// manifest is rebuilt.
+func (m *Manager) Manifest() (Manifest, error) {
+	manifest := m.readManifest()
+
+	files, err := ioutil.ReadDir(m.opts.Home)
+	if os.IsNotExist(err) {
+		return Manifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	present := map[string]os.FileInfo{}
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || binaryOfName(name) == "" || strings.HasSuffix(name, ".download") {
+			continue
+		}
+		present[name] = file
+	}
+
+	for name := range manifest {
+		if _, ok := present[name]; !ok {
+			delete(manifest, name)
+		}
+	}
+
+	for name, file := range present {
+		if _, ok := manifest[name]; ok {
+			continue
+		}
+
+		sum, err := fileChecksum(filepath.Join(m.opts.Home, name))
+		if err != nil {
+			return nil, err
+		}
+
+		version, sys, machine := platformFromBinaryName(name)
+		manifest[name] = ManifestEntry{
+			Version:   version,
+			OS:        sys,
+			Arch:      machine,
+			SHA256:    sum,
+			Installed: file.ModTime(),
+			Rebuilt:   true,
+		}
+	}
+
+	return manifest, nil
+}
+
+// readManifest - reads the entries recorded at install time, treating a
+// missing or corrupt file as empty
+func (m *Manager) readManifest() Manifest {
+	manifest := Manifest{}
+
+	data, err := ioutil.ReadFile(filepath.Join(m.opts.Home, ManifestFile))
+	if err != nil {
+		return manifest
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil || manifest == nil {
+		return Manifest{}
+	}
+
+	return manifest
+}
+
+// updateManifest - applies update to the manifest as last written and writes
+// it back. The manifest is read again under manifestLock so concurrent
+// installs don't drop each other's entries.
+func (m *Manager) updateManifest(update func(Manifest)) error {
+	m.manifestLock.Lock()
+	defer m.manifestLock.Unlock()
+
+	manifest := m.readManifest()
+	update(manifest)
+
+	return m.writeManifest(manifest)
+}
+
+// writeManifest - replaces the manifest atomically so a crash never leaves it
+// half written. Rebuilt entries are left out, their checksums were never
+// checked. Callers hold manifestLock.
+func (m *Manager) writeManifest(manifest Manifest) error {
+	recorded := Manifest{}
+	for name, entry := range manifest {
+		if !entry.Rebuilt {
+			recorded[name] = entry
+		}
+	}
+
+	data, err := json.MarshalIndent(recorded, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// A temporary file of its own, another process may be writing too
+	tmp, err := ioutil.TempFile(m.opts.Home, ManifestFile+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(append(data, '\n'))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), filepath.Join(m.opts.Home, ManifestFile))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return nil
+}
+
+// platformFromBinaryName - splits a kubectl file name into the version and
+// the platform it was built for
+func platformFromBinaryName(name string) (string, string, string) {
+	if sys, machine, ok := crossBinaryPlatform(name); ok {
+		name = strings.TrimSuffix(strings.TrimSuffix(name, ".exe"), "-"+sys+"-"+machine)
+		return strings.TrimPrefix(name, "kubectl-"), sys, machine
+	}
+
+	return VersionFromBinaryName(name), runtime.GOOS, runtime.GOARCH
+}
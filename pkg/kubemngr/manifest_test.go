@@ -0,0 +1,134 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestConcurrentInstallsKeepEveryManifestEntry(t *testing.T) {
+	versions := []string{"v1.18.0", "v1.19.0", "v1.20.0", "v1.21.0", "v1.22.0", "v1.23.0"}
+
+	files := map[string][]byte{}
+	for _, version := range versions {
+		files["/"+version+"/bin/linux/amd64/kubectl"] = fakeELF
+		files["/"+version+"/bin/linux/amd64/kubectl.sha256"] = checksumOf(fakeELF)
+	}
+	server := releaseServer(files)
+	defer server.Close()
+	m, cleanup := testManager(t, server)
+	defer cleanup()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(versions))
+	for _, version := range versions {
+		wg.Add(1)
+		go func(version string) {
+			defer wg.Done()
+			if _, err := m.Install(context.Background(), linuxInstall(version)); err != nil {
+				errs <- fmt.Errorf("%s: %w", version, err)
+			}
+		}(version)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	manifest := m.readManifest()
+	if len(manifest) != len(versions) {
+		t.Errorf("got %d manifest entries, want %d", len(manifest), len(versions))
+	}
+
+	entries, err := ioutil.ReadDir(m.opts.Home)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			t.Errorf("%s was left behind", entry.Name())
+		}
+	}
+}
+
+func TestUntrackedBinariesAreNotTrusted(t *testing.T) {
+	server := releaseServer(map[string][]byte{
+		"/v1.21.0/bin/linux/amd64/kubectl":        fakeELF,
+		"/v1.21.0/bin/linux/amd64/kubectl.sha256": checksumOf(fakeELF),
+	})
+	defer server.Close()
+	m, cleanup := testManager(t, server)
+	defer cleanup()
+
+	if _, err := m.Install(context.Background(), linuxInstall("v1.21.0")); err != nil {
+		t.Fatal(err)
+	}
+
+	// A binary kubemngr didn't install
+	swapped := BinaryPath(m.opts.Home, "v1.22.0")
+	if err := ioutil.WriteFile(swapped, fakeMachO, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := m.Manifest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !manifest[filepath.Base(swapped)].Rebuilt {
+		t.Errorf("%s isn't marked as rebuilt", filepath.Base(swapped))
+	}
+
+	// Writing the reconciled manifest back mustn't record the untracked checksum
+	m.manifestLock.Lock()
+	err = m.writeManifest(manifest)
+	m.manifestLock.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := m.readManifest()[filepath.Base(swapped)]; ok {
+		t.Errorf("the rebuilt entry for %s was written", filepath.Base(swapped))
+	}
+
+	// Reinstalling mustn't record the untracked checksums
+	opts := linuxInstall("v1.21.0")
+	opts.Force = true
+	installed, err := m.Install(context.Background(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := m.Verify("", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, result := range results {
+		want := VerifyUnknown
+		if result.Path == installed.Path {
+			want = VerifyOK
+		}
+		if result.Status != want {
+			t.Errorf("%s: got %s, want %s", result.Version, result.Status, want)
+		}
+	}
+}
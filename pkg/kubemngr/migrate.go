@@ -0,0 +1,164 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Rename is a kubectl binary moved to the name current releases use
+type Rename struct {
+	From string
+	To   string
+}
+
+// MigrateNames - renames kubectl binaries stored under names older releases
+// used: versions without the v prefix and builds for this machine stored with
+// the os-arch suffix of cross-platform builds. Their manifest entries move
+// with them and a kubectl link to a renamed binary is re-pointed. Binaries
+// whose new name is already taken are left alone. With dryRun nothing is
+// changed and only the renames that would happen are returned.
+func (m *Manager) MigrateNames(dryRun bool) ([]Rename, error) {
+	files, err := ioutil.ReadDir(m.opts.Home)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	hostSys, hostMachine, err := hostPlatform()
+	if err != nil {
+		return nil, err
+	}
+
+	var renames []Rename
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || !strings.HasPrefix(name, "kubectl-") || strings.HasSuffix(name, ".download") {
+			continue
+		}
+
+		raw, sys, machine := platformFromBinaryName(name)
+		if ValidateVersion(raw) != nil {
+			continue
+		}
+
+		version := NormalizeVersion(raw)
+		to := BinaryPath(m.opts.Home, version)
+		if isCrossBinaryName(name) && (sys != hostSys || machine != hostMachine) {
+			to = CrossBinaryPath(m.opts.Home, version, sys, machine)
+		}
+
+		from := filepath.Join(m.opts.Home, name)
+		if from == to {
+			continue
+		}
+		if _, err := os.Lstat(to); err == nil {
+			m.debugf("not renaming %s, %s already exists", name, filepath.Base(to))
+			continue
+		}
+
+		renames = append(renames, Rename{From: from, To: to})
+	}
+
+	if dryRun || len(renames) == 0 {
+		return renames, nil
+	}
+
+	link := m.LinkPath()
+	linked, _ := readLink(link)
+
+	for _, rename := range renames {
+		if err := os.Rename(rename.From, rename.To); err != nil {
+			return nil, err
+		}
+
+		from, to := filepath.Base(rename.From), filepath.Base(rename.To)
+		if linked == rename.From {
+			if err := replaceLink(rename.To, link); err != nil {
+				return nil, fmt.Errorf("renamed %s to %s but unable to update %s: %w", from, to, link, err)
+			}
+		}
+	}
+
+	err = m.updateManifest(func(manifest Manifest) {
+		for _, rename := range renames {
+			from, to := filepath.Base(rename.From), filepath.Base(rename.To)
+			if entry, ok := manifest[from]; ok {
+				delete(manifest, from)
+				manifest[to] = entry
+			}
+		}
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to update %s: %w", ManifestFile, err)
+	}
+
+	return renames, nil
+}
+
+// AsdfInstall is a kubectl version installed by asdf's kubectl plugin
+type AsdfInstall struct {
+	// Version is the name of the version's directory, as asdf was given it
+	Version string
+
+	// Path is the kubectl binary
+	Path string
+}
+
+// AsdfInstalls - lists the kubectl versions installed by asdf, found in
+// $ASDF_DATA_DIR/installs/kubectl or ~/.asdf/installs/kubectl. Versions
+// without a kubectl binary are left out and none is an empty list.
+func AsdfInstalls() ([]AsdfInstall, error) {
+	dataDir := os.Getenv("ASDF_DATA_DIR")
+	if dataDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		dataDir = filepath.Join(homeDir, ".asdf")
+	}
+
+	dir := filepath.Join(dataDir, "installs", "kubectl")
+	files, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var installs []AsdfInstall
+	for _, file := range files {
+		if !file.IsDir() {
+			continue
+		}
+
+		path := filepath.Join(dir, file.Name(), "bin", "kubectl"+ExeSuffix)
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		installs = append(installs, AsdfInstall{Version: file.Name(), Path: path})
+	}
+
+	return installs, nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ReleaseURL is the official location of kubectl releases. It redirects to
+// the CDN actually serving the files.
+const ReleaseURL = "https://dl.k8s.io/release"
+
+// LegacyReleaseURL is the bucket releases were served from before dl.k8s.io,
+// used as a fallback when ReleaseURL can't be reached
+const LegacyReleaseURL = "https://storage.googleapis.com/kubernetes-release/release"
+
+// Mirrors are the presets accepted as Options.Mirror in place of a URL
+var Mirrors = map[string]string{
+	"default": ReleaseURL,
+	"gcs":     LegacyReleaseURL,
+	"cn":      "https://mirror.azure.cn/kubernetes/kubectl",
+}
+
+// BaseURL - returns the base URL kubectl binaries are downloaded from,
+// taken from Options.Mirror when set
+func (m *Manager) BaseURL() (string, error) {
+	mirror := m.opts.Mirror
+	if mirror == "" {
+		return ReleaseURL, nil
+	}
+
+	if preset, ok := Mirrors[mirror]; ok {
+		return preset, nil
+	}
+
+	u, err := url.Parse(mirror)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid mirror %q, expected an http(s) URL or one of the presets: default, gcs, cn", mirror)
+	}
+
+	return strings.TrimSuffix(mirror, "/"), nil
+}
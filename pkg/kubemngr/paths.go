@@ -0,0 +1,368 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DefaultHome - returns the directory kubectl binaries are stored in by default.
+// KUBEMNGR_HOME takes precedence, then an existing ~/.kubemngr from older releases,
+// then $XDG_DATA_HOME/kubemngr.
+func DefaultHome() (string, error) {
+	if env, ok := os.LookupEnv("KUBEMNGR_HOME"); ok && env != "" {
+		abs, err := filepath.Abs(env)
+		if err != nil {
+			return "", fmt.Errorf("invalid KUBEMNGR_HOME %q: %w", env, err)
+		}
+		return abs, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(homeDir, ".kubemngr")
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		dataHome, err := XDGDir("XDG_DATA_HOME", ".local/share")
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(dataHome, "kubemngr")
+	}
+
+	return dir, nil
+}
+
+// Dir - returns the directory kubectl binaries are stored in, creating it if missing
+func (m *Manager) Dir() (string, error) {
+	if err := ensureDir(m.opts.Home, m.opts.DirMode); err != nil {
+		return "", err
+	}
+
+	return m.opts.Home, nil
+}
+
+// PathFor - returns the path of an installed version of binary, or ErrNotInstalled
+func (m *Manager) PathFor(binary, version string) (string, error) {
+	if binary == DefaultBinary {
+		return m.Path(version)
+	}
+
+	version = NormalizeVersion(version)
+	path := BinaryPathFor(m.opts.Home, binary, version)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", fmt.Errorf("%s %s is %w", binary, version, ErrNotInstalled)
+	}
+
+	return path, nil
+}
+
+// Path - returns the path of an installed version for this machine, or ErrNotInstalled
+func (m *Manager) Path(version string) (string, error) {
+	version = NormalizeVersion(version)
+
+	path := BinaryPath(m.opts.Home, version)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// Say so when only builds that can't run here are installed
+		if others := m.crossPlatforms(version); len(others) > 0 {
+			return "", fmt.Errorf("kubectl %s is %w for this machine, only for %s", version, ErrNotInstalled, strings.Join(others, ", "))
+		}
+		return "", fmt.Errorf("kubectl %s is %w", version, ErrNotInstalled)
+	}
+
+	return path, nil
+}
+
+// PlatformPath - returns the path of an installed version built for sys and
+// machine, or ErrNotInstalled. Empty values mean this machine's OS and arch.
+func (m *Manager) PlatformPath(version, sys, machine string) (string, error) {
+	version = NormalizeVersion(version)
+
+	path, sys, machine, err := m.platformPath(version, sys, machine)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", fmt.Errorf("kubectl %s for %s/%s is %w", version, sys, machine, ErrNotInstalled)
+	}
+
+	return path, nil
+}
+
+// platformPath - returns where version for sys and machine is stored, with
+// the OS and arch they resolve to
+func (m *Manager) platformPath(version, sys, machine string) (string, string, string, error) {
+	hostSys, hostMachine, err := hostPlatform()
+	if err != nil {
+		return "", "", "", err
+	}
+
+	sys, machine, err = targetPlatform(hostSys, hostMachine, sys, machine)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	if sys == hostSys && machine == hostMachine {
+		return BinaryPath(m.opts.Home, version), sys, machine, nil
+	}
+
+	return CrossBinaryPath(m.opts.Home, version, sys, machine), sys, machine, nil
+}
+
+// crossPlatforms - lists the other platforms version is installed for, as os/arch
+func (m *Manager) crossPlatforms(version string) []string {
+	var platforms []string
+	for sys, arches := range releasePlatforms {
+		for _, arch := range arches {
+			if _, err := os.Stat(CrossBinaryPath(m.opts.Home, version, sys, arch)); err == nil {
+				platforms = append(platforms, sys+"/"+arch)
+			}
+		}
+	}
+	sort.Strings(platforms)
+
+	return platforms
+}
+
+// XDGDir - returns the XDG base directory in env, or fallback relative to the
+// home directory when it is unset. The spec says relative paths are invalid.
+func XDGDir(env, fallback string) (string, error) {
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, filepath.FromSlash(fallback)), nil
+}
+
+// BinDir - returns the directory inside home holding the kubectl symlink, the
+// one directory that needs to be on PATH
+func BinDir(home string) string {
+	return filepath.Join(home, "bin")
+}
+
+// LinkPath - returns the path of the kubectl symlink pointing at the active version
+func (m *Manager) LinkPath() string {
+	return m.LinkPathFor(DefaultBinary)
+}
+
+// LinkPathFor - returns the path of the symlink pointing at the active version of binary
+func (m *Manager) LinkPathFor(binary string) string {
+	return filepath.Join(BinDir(m.opts.Home), binary+ExeSuffix)
+}
+
+// LegacyLinkPath - returns where releases before the bin directory kept the kubectl symlink
+func LegacyLinkPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(homeDir, ".local", "bin", "kubectl"+ExeSuffix), nil
+}
+
+// BinaryPath - returns the path a kubectl version is stored at inside dir
+func BinaryPath(dir, version string) string {
+	return BinaryPathFor(dir, DefaultBinary, version)
+}
+
+// BinaryPathFor - returns the path a version of binary, such as kubeadm, is
+// stored at inside dir
+func BinaryPathFor(dir, binary, version string) string {
+	return filepath.Join(dir, binary+"-"+version+ExeSuffix)
+}
+
+// CrossBinaryPath - returns where the build of a kubectl version for another
+// platform is kept, e.g. kubectl-v1.21.0-linux-arm64
+func CrossBinaryPath(dir, version, sys, machine string) string {
+	name := fmt.Sprintf("kubectl-%s-%s-%s", version, sys, machine)
+	if sys == "windows" {
+		name += ".exe"
+	}
+
+	return filepath.Join(dir, name)
+}
+
+// isCrossBinaryName - reports whether name is a build for another platform
+// stored by CrossBinaryPath
+func isCrossBinaryName(name string) bool {
+	_, _, ok := crossBinaryPlatform(name)
+	return ok
+}
+
+// crossBinaryPlatform - returns the OS and arch of a build stored by CrossBinaryPath
+func crossBinaryPlatform(name string) (string, string, bool) {
+	name = strings.TrimSuffix(name, ".exe")
+	for sys, arches := range releasePlatforms {
+		for _, arch := range arches {
+			if strings.HasSuffix(name, "-"+sys+"-"+arch) {
+				return sys, arch, true
+			}
+		}
+	}
+
+	return "", "", false
+}
+
+// VersionFromBinaryName - extracts the version from a kubectl-<version> file
+// name, or the name of any other binary in Binaries
+func VersionFromBinaryName(name string) string {
+	name = strings.TrimSuffix(name, ExeSuffix)
+	if binary := binaryOfName(name); binary != "" {
+		return strings.TrimPrefix(name, binary+"-")
+	}
+
+	return name
+}
+
+// binaryOfName - returns which of Binaries a file name in Home is a version
+// of, or an empty string. kubectl-convert-v1.21.0 is kubectl-convert's, not kubectl's.
+func binaryOfName(name string) string {
+	found := ""
+	for _, binary := range Binaries {
+		if strings.HasPrefix(name, binary+"-") && len(binary) > len(found) {
+			found = binary
+		}
+	}
+
+	return found
+}
+
+// EnsureDir - creates dir if it does not exist and makes sure neither it nor
+// any of its parents is a regular file
+func EnsureDir(dir string) error {
+	return ensureDir(dir, DefaultDirMode)
+}
+
+// ensureDir - EnsureDir creating dir with mode
+func ensureDir(dir string, mode os.FileMode) error {
+	info, err := os.Stat(dir)
+	if os.IsNotExist(err) {
+		// Only dir itself gets mode, parents such as ~/.local/share are shared
+		if err = os.MkdirAll(filepath.Dir(dir), DefaultDirMode); err == nil {
+			if err = os.Mkdir(dir, mode); err == nil || os.IsExist(err) {
+				return nil
+			}
+		}
+	}
+	if err != nil {
+		// A file in the way only shows up as "not a directory", name it instead
+		if file := fileInPath(dir); file != "" {
+			return notDirError(file)
+		}
+		return err
+	}
+
+	if !info.IsDir() {
+		return notDirError(dir)
+	}
+
+	return nil
+}
+
+// fileInPath - returns the nearest of dir and its parents that exists, if it
+// isn't a directory
+func fileInPath(dir string) string {
+	for path := dir; ; path = filepath.Dir(path) {
+		if info, err := os.Stat(path); err == nil {
+			if info.IsDir() {
+				return ""
+			}
+			return path
+		}
+
+		if filepath.Dir(path) == path {
+			return ""
+		}
+	}
+}
+
+func notDirError(path string) error {
+	return fmt.Errorf("%s exists but is not a directory, please move or remove it", path)
+}
+
+// copyFile - copies the contents of src to dst, replacing dst, and returns
+// their SHA256 digest computed on the way through. dst is removed when it
+// can't be written in full.
+func copyFile(src, dst string) ([]byte, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(out, hash), in)
+	if err == nil {
+		err = out.Sync()
+	}
+	if err != nil {
+		out.Close()
+		os.Remove(dst)
+		return nil, err
+	}
+
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return nil, err
+	}
+
+	// A full disk or quota can cut the file short without io.Copy noticing
+	info, err := os.Stat(dst)
+	if err == nil && info.Size() != n {
+		err = fmt.Errorf("%s is incomplete, %d of %d bytes were written", dst, info.Size(), n)
+	}
+	if err != nil {
+		os.Remove(dst)
+		return nil, err
+	}
+
+	return hash.Sum(nil), nil
+}
+
+// syncFile - flushes path to disk, so a write the filesystem couldn't
+// complete shows up as an error now rather than as a truncated binary later
+func syncFile(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
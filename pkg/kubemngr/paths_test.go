@@ -0,0 +1,60 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDirFileInTheWay(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "kubemngr-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	file := filepath.Join(tmp, ".kubemngr")
+	if err := ioutil.WriteFile(file, []byte("not a directory"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both the directory itself and one of its parents being a file name the file
+	for _, home := range []string{file, filepath.Join(file, "nested")} {
+		m, err := New(Options{Home: home})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = m.Dir()
+		if err == nil {
+			t.Fatalf("%s: got no error", home)
+		}
+		if want := file + " exists but is not a directory"; !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: got %q, want it to say %q", home, err, want)
+		}
+	}
+
+	// The file is left alone
+	data, err := ioutil.ReadFile(file)
+	if err != nil || string(data) != "not a directory" {
+		t.Errorf("the file in the way was changed: %q, %v", data, err)
+	}
+}
@@ -0,0 +1,105 @@
+//go:build !windows
+// +build !windows
+
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// ExeSuffix is appended to kubectl binary names on this platform
+const ExeSuffix = ""
+
+// shimSuffix is appended to the name of the kubectl shim
+const shimSuffix = ""
+
+func getOSInfo() (uname, error) {
+	var utsname unix.Utsname
+
+	if err := unix.Uname(&utsname); err != nil {
+		return uname{}, fmt.Errorf("unable to detect OS: %w", err)
+	}
+
+	return uname{
+		Sysname: string(bytes.Trim(utsname.Sysname[:], "\x00")),
+		Machine: string(bytes.Trim(utsname.Machine[:], "\x00")),
+	}, nil
+}
+
+// linkKubectl - points link at the kubectl binary in target
+func linkKubectl(target, link string) error {
+	return os.Symlink(target, link)
+}
+
+// shimScript - returns a shell script that runs kubectl through exe
+func shimScript(exe string) string {
+	quoted := "'" + strings.Replace(exe, "'", `'\''`, -1) + "'"
+	return "#!/bin/sh\n# " + shimMarker + ", regenerate with kubemngr rehash\nexec " + quoted + ` exec -- "$@"` + "\n"
+}
+
+// Exec - replaces the current process with kubectl at path
+func Exec(path string, args []string) error {
+	argv := append([]string{"kubectl"}, args...)
+	return syscall.Exec(path, argv, os.Environ())
+}
+
+// lockFile - opens path and takes an exclusive advisory lock on it, returning
+// errLocked when another process holds it. Closing the file releases the lock.
+func lockFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := unix.Flock(int(f.Fd()), unix.LOCK_EX|unix.LOCK_NB); err != nil {
+		f.Close()
+		if err == unix.EWOULDBLOCK {
+			return nil, errLocked
+		}
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// freeSpace - returns the bytes available to unprivileged users on the filesystem holding dir
+func freeSpace(dir string) (uint64, error) {
+	var stat unix.Statfs_t
+	if err := unix.Statfs(dir, &stat); err != nil {
+		return 0, err
+	}
+
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
+
+// umask - returns the process umask. It can only be read by setting it, so
+// it is put straight back. Other goroutines creating files meanwhile would get
+// no umask, so New reads it once for the Manager.
+func umask() os.FileMode {
+	mask := unix.Umask(0)
+	unix.Umask(mask)
+
+	return os.FileMode(mask)
+}
@@ -0,0 +1,128 @@
+//go:build windows
+// +build windows
+
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// ExeSuffix is appended to kubectl binary names on this platform
+const ExeSuffix = ".exe"
+
+// shimSuffix is appended to the name of the kubectl shim
+const shimSuffix = ".cmd"
+
+var procGetDiskFreeSpaceEx = syscall.NewLazyDLL("kernel32.dll").NewProc("GetDiskFreeSpaceExW")
+
+// errSharingViolation is ERROR_SHARING_VIOLATION, returned when a file is already open exclusively
+const errSharingViolation = syscall.Errno(32)
+
+// getOSInfo - Windows has no uname, so report the platform the binary was built for
+func getOSInfo() (uname, error) {
+	machine := runtime.GOARCH
+	if machine == "amd64" {
+		machine = "x86_64"
+	}
+
+	return uname{
+		Sysname: "Windows",
+		Machine: machine,
+	}, nil
+}
+
+// linkKubectl - points link at the kubectl binary in target. Creating symlinks
+// needs elevated privileges on most Windows setups, so fall back to a copy.
+func linkKubectl(target, link string) error {
+	if err := os.Symlink(target, link); err == nil {
+		return nil
+	}
+
+	_, err := copyFile(target, link)
+	return err
+}
+
+// shimScript - returns a batch file that runs kubectl through exe
+func shimScript(exe string) string {
+	return "@echo off\r\nrem " + shimMarker + ", regenerate with kubemngr rehash\r\n\"" + exe + "\" exec -- %*\r\n"
+}
+
+// Exec - runs kubectl at path and exits with its status, as Windows
+// can't replace the running process
+func Exec(path string, args []string) error {
+	kubectl := exec.Command(path, args...)
+	kubectl.Stdin = os.Stdin
+	kubectl.Stdout = os.Stdout
+	kubectl.Stderr = os.Stderr
+
+	if err := kubectl.Run(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			os.Exit(exitErr.ExitCode())
+		}
+		return err
+	}
+
+	os.Exit(0)
+	return nil
+}
+
+// lockFile - opens path without sharing it, returning errLocked when another
+// process has it open. Closing the file releases the lock.
+func lockFile(path string) (*os.File, error) {
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+
+	handle, err := syscall.CreateFile(name, syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil,
+		syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err == errSharingViolation {
+		return nil, errLocked
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return os.NewFile(uintptr(handle), path), nil
+}
+
+// freeSpace - returns the bytes available to the current user on the volume holding dir
+func freeSpace(dir string) (uint64, error) {
+	name, err := syscall.UTF16PtrFromString(dir)
+	if err != nil {
+		return 0, err
+	}
+
+	var available uint64
+	ok, _, err := procGetDiskFreeSpaceEx.Call(uintptr(unsafe.Pointer(name)), uintptr(unsafe.Pointer(&available)), 0, 0)
+	if ok == 0 {
+		return 0, err
+	}
+
+	return available, nil
+}
+
+// umask - Windows has no umask, file modes only control the read-only attribute
+func umask() os.FileMode {
+	return 0
+}
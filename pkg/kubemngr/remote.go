@@ -0,0 +1,265 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+)
+
+// ReleasesURL lists Kubernetes releases through the GitHub API. The list is
+// paginated, later pages are followed through the Link header.
+const ReleasesURL = "https://api.github.com/repos/kubernetes/kubernetes/releases?per_page=100"
+
+// maxReleasePages stops a Link header that never ends from looping forever
+const maxReleasePages = 50
+
+const (
+	// IndexFile is the cached remote version list in Options.CacheDir
+	IndexFile = "remote-versions.json"
+
+	// DefaultIndexTTL is how long the cached version list is used before it is fetched again
+	DefaultIndexTTL = time.Hour
+)
+
+// versionIndex is the remote version list as cached on disk
+type versionIndex struct {
+	Fetched  time.Time `json:"fetched"`
+	Versions []string  `json:"versions"`
+}
+
+// RemoteVersions - lists the kubectl versions available to install, newest
+// first. The list cached in Options.CacheDir is used while it is younger than
+// Options.IndexTTL, when the remote can't be reached and with Options.Offline.
+func (m *Manager) RemoteVersions() ([]*version.Version, error) {
+	cached, cacheErr := m.readIndex()
+	if m.opts.Offline {
+		if cacheErr != nil {
+			return nil, fmt.Errorf("%w: no cached version list, it needs fetching once while online", ErrOffline)
+		}
+		m.debugf("Using the version list cached at %s", cached.Fetched.Format(time.RFC3339))
+		return cached.parse()
+	}
+	if cacheErr == nil && time.Since(cached.Fetched) < m.opts.IndexTTL {
+		m.debugf("Using the version list cached at %s", cached.Fetched.Format(time.RFC3339))
+		return cached.parse()
+	}
+
+	versions, err := m.fetchRemoteVersions()
+	if err != nil {
+		if cacheErr != nil {
+			return nil, err
+		}
+
+		m.warnf("%v. Using the version list cached at %s, it may be out of date", err, cached.Fetched.Local().Format("2006-01-02 15:04"))
+		return cached.parse()
+	}
+
+	// A failed cache write only means fetching again next time
+	if err := m.writeIndex(versions); err != nil {
+		m.debugf("Unable to cache the version list: %v", err)
+	}
+
+	return versions, nil
+}
+
+// CachedRemoteVersions - lists the cached remote versions, newest first,
+// however old the cache is and without touching the network
+func (m *Manager) CachedRemoteVersions() ([]*version.Version, error) {
+	cached, err := m.readIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	return cached.parse()
+}
+
+// UpdateIndex - fetches the remote version list, replacing the cached one
+// however fresh it is
+func (m *Manager) UpdateIndex() ([]*version.Version, error) {
+	versions, err := m.fetchRemoteVersions()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.writeIndex(versions); err != nil {
+		return nil, fmt.Errorf("unable to cache the version list: %w", err)
+	}
+
+	return versions, nil
+}
+
+// readIndex - reads the cached version list, an error when there is none
+func (m *Manager) readIndex() (*versionIndex, error) {
+	if m.opts.CacheDir == "" {
+		return nil, os.ErrNotExist
+	}
+
+	data, err := ioutil.ReadFile(filepath.Join(m.opts.CacheDir, IndexFile))
+	if err != nil {
+		return nil, err
+	}
+
+	index := &versionIndex{}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, err
+	}
+
+	return index, nil
+}
+
+// writeIndex - caches versions in Options.CacheDir, doing nothing without one
+func (m *Manager) writeIndex(versions []*version.Version) error {
+	if m.opts.CacheDir == "" {
+		return nil
+	}
+
+	index := versionIndex{Fetched: time.Now()}
+	for _, v := range versions {
+		index.Versions = append(index.Versions, v.Original())
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+
+	if err := EnsureDir(m.opts.CacheDir); err != nil {
+		return err
+	}
+
+	// Write next to the index and rename so a concurrent read never sees half of it
+	path := filepath.Join(m.opts.CacheDir, IndexFile)
+	if err := ioutil.WriteFile(path+".tmp", data, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(path+".tmp", path)
+}
+
+// parse - returns the cached versions, newest first
+func (i *versionIndex) parse() ([]*version.Version, error) {
+	list := make([]*version.Version, 0, len(i.Versions))
+	for _, raw := range i.Versions {
+		v, err := version.NewVersion(raw)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse cached remote versions: %v", err)
+		}
+		list = append(list, v)
+	}
+
+	return list, nil
+}
+
+// fetchRemoteVersions - lists the releases on GitHub, newest first
+func (m *Manager) fetchRemoteVersions() ([]*version.Version, error) {
+	return m.fetchReleases(ReleasesURL)
+}
+
+// fetchReleases - lists the releases at url and every page after it that the
+// Link header points to, newest first
+func (m *Manager) fetchReleases(url string) ([]*version.Version, error) {
+	list := []*version.Version{}
+	for page := 1; url != ""; page++ {
+		if page > maxReleasePages {
+			return nil, fmt.Errorf("unable to fetch remote versions: more than %d pages of releases", maxReleasePages)
+		}
+
+		versions, next, err := m.fetchReleasePage(url)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, versions...)
+		url = next
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].GreaterThan(list[j])
+	})
+
+	return list, nil
+}
+
+// fetchReleasePage - lists the releases on one page of the GitHub API and
+// returns the URL of the next page, empty on the last one
+func (m *Manager) fetchReleasePage(url string) ([]*version.Version, string, error) {
+	m.debugf("Fetching %s", url)
+	res, err := m.HTTPClient().Get(url)
+	if err != nil {
+		return nil, "", m.requestError("unable to fetch remote versions", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, "", GitHubResponseError("unable to fetch remote versions", url, res)
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to read remote versions: %v", err)
+	}
+
+	releases := []struct {
+		TagName string `json:"tag_name"`
+	}{}
+	if err := json.Unmarshal(body, &releases); err != nil {
+		return nil, "", fmt.Errorf("unable to parse remote versions: %v", err)
+	}
+
+	list := []*version.Version{}
+	for _, release := range releases {
+		v, err := version.NewVersion(release.TagName)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to parse remote versions: %v", err)
+		}
+		list = append(list, v)
+	}
+
+	return list, nextPage(res.Header.Get("Link")), nil
+}
+
+// nextPage - returns the rel="next" URL of a Link header such as
+// <https://api.github.com/...?page=2>; rel="next", <...>; rel="last"
+func nextPage(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		fields := strings.Split(part, ";")
+		if len(fields) < 2 {
+			continue
+		}
+
+		target := strings.TrimSpace(fields[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		for _, param := range fields[1:] {
+			if strings.TrimSpace(param) == `rel="next"` {
+				return strings.Trim(target, "<>")
+			}
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,83 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNextPage(t *testing.T) {
+	tests := []struct {
+		link string
+		want string
+	}{
+		{"", ""},
+		{`<https://api.github.com/r?page=2>; rel="next", <https://api.github.com/r?page=9>; rel="last"`, "https://api.github.com/r?page=2"},
+		{`<https://api.github.com/r?page=1>; rel="prev", <https://api.github.com/r?page=3>; rel="next"`, "https://api.github.com/r?page=3"},
+		{`<https://api.github.com/r?page=1>; rel="first", <https://api.github.com/r?page=1>; rel="prev"`, ""},
+	}
+
+	for _, test := range tests {
+		if got := nextPage(test.link); got != test.want {
+			t.Errorf("%q: got %q, want %q", test.link, got, test.want)
+		}
+	}
+}
+
+func TestFetchReleasesFollowsPages(t *testing.T) {
+	pages := map[string]string{
+		"1": `[{"tag_name": "v1.22.0"}, {"tag_name": "v1.21.0"}]`,
+		"2": `[{"tag_name": "v1.20.0"}, {"tag_name": "v1.21.1"}]`,
+		"3": `[{"tag_name": "v1.19.0"}]`,
+	}
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		if page == "" {
+			page = "1"
+		}
+		switch page {
+		case "1":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/releases?page=2>; rel="next", <%s/releases?page=3>; rel="last"`, server.URL, server.URL))
+		case "2":
+			w.Header().Set("Link", fmt.Sprintf(`<%s/releases?page=3>; rel="next"`, server.URL))
+		}
+		fmt.Fprint(w, pages[page])
+	}))
+	defer server.Close()
+	m, cleanup := testManager(t, server)
+	defer cleanup()
+
+	versions, err := m.fetchReleases(server.URL + "/releases")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"v1.22.0", "v1.21.1", "v1.21.0", "v1.20.0", "v1.19.0"}
+	if len(versions) != len(want) {
+		t.Fatalf("got %d versions, want %d", len(versions), len(want))
+	}
+	for i, v := range versions {
+		if v.Original() != want[i] {
+			t.Errorf("version %d: got %s, want %s", i, v.Original(), want[i])
+		}
+	}
+}
@@ -0,0 +1,179 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	getter "github.com/hashicorp/go-getter"
+)
+
+// BinaryRepair describes a binary recorded in the manifest that
+// RepairBinaries found missing or corrupted
+type BinaryRepair struct {
+	// Name is the file name of the binary in the kubemngr directory
+	Name    string
+	Version string
+
+	// Missing is set when the binary was gone, otherwise its SHA256 no
+	// longer matched the one recorded when it was installed
+	Missing bool
+
+	// Err is why the binary couldn't be reinstalled, nil when it was
+	Err error
+}
+
+// RepairBinaries - compares the binaries recorded in the manifest with the
+// files in the kubemngr directory and reinstalls those that are missing or
+// whose SHA256 differs from the one recorded at install time. Binaries are
+// installed again from where they came from, a failure is recorded in Err
+// and doesn't stop the others from being repaired.
+func (m *Manager) RepairBinaries(ctx context.Context, progress getter.ProgressTracker) ([]BinaryRepair, error) {
+	manifest := m.readManifest()
+
+	names := make([]string, 0, len(manifest))
+	for name := range manifest {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var repairs []BinaryRepair
+	for _, name := range names {
+		entry := manifest[name]
+		repair := BinaryRepair{Name: name, Version: entry.Version}
+
+		sum, err := fileChecksum(filepath.Join(m.opts.Home, name))
+		switch {
+		case os.IsNotExist(err):
+			repair.Missing = true
+		case err != nil:
+			return nil, err
+		case sum == entry.SHA256:
+			continue
+		}
+
+		opts := InstallOptions{
+			Version:  entry.Version,
+			Binary:   binaryOfName(name),
+			Force:    true,
+			Progress: progress,
+		}
+		if isCrossBinaryName(name) {
+			opts.OS, opts.Arch = entry.OS, entry.Arch
+		} else {
+			opts.Rosetta = entry.Arch != runtime.GOARCH
+		}
+		if !strings.HasPrefix(entry.Source, "http://") && !strings.HasPrefix(entry.Source, "https://") {
+			opts.From = entry.Source
+		}
+
+		m.infof("Reinstalling %s %s", opts.Binary, entry.Version)
+		_, repair.Err = m.Install(ctx, opts)
+		repairs = append(repairs, repair)
+
+		if ctx.Err() != nil {
+			return repairs, ctx.Err()
+		}
+	}
+
+	return repairs, nil
+}
+
+// LinkRepair describes what RepairLink did to the kubectl link
+type LinkRepair struct {
+	// Link is the path of the kubectl link
+	Link string
+
+	// Missing is the binary a dangling link pointed to, empty when it wasn't dangling
+	Missing string
+
+	// Cleared is set when the dangling link was removed
+	Cleared bool
+
+	// Version is the version the link points to after the repair, empty
+	// when it wasn't changed or no version could be linked
+	Version string
+}
+
+// Repaired - reports whether anything was changed
+func (r *LinkRepair) Repaired() bool {
+	return r.Cleared || r.Version != ""
+}
+
+// RepairLink - fixes a kubectl link pointing at a binary that no longer
+// exists. It is re-pointed at the same version when that is still installed,
+// for example in a different home, and removed otherwise. A missing link is
+// then created for fallback, such as the default version, if it is installed.
+func (m *Manager) RepairLink(fallback string) (*LinkRepair, error) {
+	link := m.LinkPath()
+	repair := &LinkRepair{Link: link}
+
+	info, err := os.Lstat(link)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	// Windows copies kubectl when it can't symlink, a copy can't dangle
+	if err == nil && info.Mode()&os.ModeSymlink == 0 {
+		return repair, nil
+	}
+
+	if err == nil {
+		if _, err := os.Stat(link); err == nil {
+			return repair, nil
+		}
+
+		target, err := os.Readlink(link)
+		if err != nil {
+			return nil, err
+		}
+		repair.Missing = target
+
+		version := VersionFromBinaryName(filepath.Base(target))
+		if _, err := m.Path(version); err == nil {
+			if err := m.Use(version); err != nil {
+				return nil, err
+			}
+			repair.Version = NormalizeVersion(version)
+			return repair, nil
+		}
+
+		if err := os.Remove(link); err != nil {
+			return nil, err
+		}
+		repair.Cleared = true
+	}
+
+	if fallback == "" {
+		return repair, nil
+	}
+	if _, err := m.Path(fallback); err != nil {
+		return repair, nil
+	}
+
+	if err := m.Use(fallback); err != nil {
+		return nil, err
+	}
+	repair.Version = NormalizeVersion(fallback)
+
+	return repair, nil
+}
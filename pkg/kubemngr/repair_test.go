@@ -0,0 +1,81 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestRepairBinariesReinstallsFromManifest(t *testing.T) {
+	// A build for another platform, so it isn't run after being reinstalled
+	sys, body := "darwin", fakeMachO
+	if runtime.GOOS == "darwin" {
+		sys, body = "linux", fakeELF
+	}
+
+	files := map[string][]byte{}
+	for _, version := range []string{"v1.21.0", "v1.22.0", "v1.23.0"} {
+		files["/"+version+"/bin/"+sys+"/amd64/kubectl"] = body
+		files["/"+version+"/bin/"+sys+"/amd64/kubectl.sha256"] = checksumOf(body)
+	}
+	server := releaseServer(files)
+	defer server.Close()
+	m, cleanup := testManager(t, server)
+	defer cleanup()
+
+	paths := map[string]string{}
+	for _, version := range []string{"v1.21.0", "v1.22.0", "v1.23.0"} {
+		verifyExec := false
+		installed, err := m.Install(context.Background(), InstallOptions{Version: version, OS: sys, Arch: "amd64", VerifyExec: &verifyExec})
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths[version] = installed.Path
+	}
+
+	if err := ioutil.WriteFile(paths["v1.21.0"], []byte("corrupted"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(paths["v1.22.0"]); err != nil {
+		t.Fatal(err)
+	}
+
+	repairs, err := m.RepairBinaries(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(repairs) != 2 {
+		t.Fatalf("got %d repairs, want 2: %+v", len(repairs), repairs)
+	}
+	for i, want := range []BinaryRepair{{Version: "v1.21.0"}, {Version: "v1.22.0", Missing: true}} {
+		if got := repairs[i]; got.Version != want.Version || got.Missing != want.Missing || got.Err != nil {
+			t.Errorf("got %+v, want %s reinstalled with Missing %v", got, want.Version, want.Missing)
+		}
+	}
+
+	for version, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil || string(data) != string(body) {
+			t.Errorf("%s wasn't restored: %v", version, err)
+		}
+	}
+}
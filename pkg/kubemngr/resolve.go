@@ -0,0 +1,192 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// releaseTag matches Kubernetes release tags such as v1.21.0 or 1.22.0-rc.1
+var releaseTag = regexp.MustCompile(`^v?(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)(-[0-9A-Za-z-]+(\.[0-9A-Za-z-]+)*)?$`)
+
+// Resolve - turns the latest, stable and latest-<major>.<minor> keywords, or a
+// semver range such as ">=1.20.0 <1.22.0" or "~1.21", into a concrete release
+// version. Pre-releases are only considered with Options.Pre, stable never picks
+// one. Any other version is returned unchanged.
+func (m *Manager) Resolve(version string) (string, error) {
+	// stable*.txt only track releases, latest*.txt include pre-releases
+	channel := "stable"
+	if m.opts.Pre {
+		channel = "latest"
+	}
+
+	var marker string
+	switch {
+	case version == "latest":
+		marker = channel + ".txt"
+	case version == "stable":
+		marker = "stable.txt"
+	case strings.HasPrefix(version, "latest-"):
+		marker = channel + "-" + strings.TrimPrefix(strings.TrimPrefix(version, "latest-"), "v") + ".txt"
+	case isConstraint(version):
+		return m.resolveConstraint(version)
+	default:
+		return version, nil
+	}
+
+	if resolved, ok := m.cachedResolution(version); ok {
+		return resolved, nil
+	}
+
+	// The markers sit next to the releases, so a mirror serves them too
+	base, err := m.BaseURL()
+	if err != nil {
+		return "", err
+	}
+	url := base + "/" + marker
+	m.debugf("Fetching %s", url)
+	res, err := m.HTTPClient().Get(url)
+	if err != nil {
+		return "", m.requestError("unable to resolve "+version, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unable to resolve %s: %s returned %s", version, url, res.Status)
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve %s: %w", version, err)
+	}
+
+	resolved := strings.TrimSpace(string(body))
+	if resolved == "" {
+		return "", fmt.Errorf("unable to resolve %s: %s is empty", version, url)
+	}
+
+	m.cacheResolution(version, resolved)
+	return resolved, nil
+}
+
+// ResolveRelease - resolves version like Resolve and returns the release tag
+// install would download for it, with the leading v
+func (m *Manager) ResolveRelease(version string) (string, error) {
+	resolved, err := m.Resolve(version)
+	if err != nil {
+		return "", err
+	}
+
+	resolved = NormalizeVersion(resolved)
+	if err := ValidateVersion(resolved); err != nil {
+		return "", err
+	}
+
+	return resolved, nil
+}
+
+// isConstraint - reports whether version is a range rather than a single version
+func isConstraint(version string) bool {
+	if _, err := semver.StrictNewVersion(strings.TrimPrefix(version, "v")); err == nil {
+		return false
+	}
+
+	_, err := semver.NewConstraint(version)
+	return err == nil
+}
+
+// resolveConstraint - picks the newest available version satisfying constraint
+func (m *Manager) resolveConstraint(constraint string) (string, error) {
+	if resolved, ok := m.cachedResolution(constraint); ok {
+		return resolved, nil
+	}
+
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return "", fmt.Errorf("invalid version range %q: %w", constraint, err)
+	}
+
+	// RemoteVersions returns the newest version first
+	versions, err := m.RemoteVersions()
+	if err != nil {
+		return "", err
+	}
+
+	for _, v := range versions {
+		candidate, err := semver.NewVersion(v.Original())
+		if err != nil {
+			continue
+		}
+
+		// Ranges never match pre-releases on their own, so compare the
+		// release a pre-release leads up to instead
+		if candidate.Prerelease() != "" {
+			if !m.opts.Pre {
+				continue
+			}
+			core, _ := candidate.SetPrerelease("")
+			candidate = &core
+		}
+
+		if c.Check(candidate) {
+			m.cacheResolution(constraint, v.Original())
+			return v.Original(), nil
+		}
+	}
+
+	return "", fmt.Errorf("no available kubectl version satisfies %q", constraint)
+}
+
+// ValidateVersion - rejects anything that doesn't look like a release tag before it ends up in a URL
+func ValidateVersion(version string) error {
+	if !releaseTag.MatchString(version) {
+		return fmt.Errorf("invalid kubectl version %q, expected a release such as v1.21.0, latest, stable or a range such as ~1.21", version)
+	}
+
+	return nil
+}
+
+// NormalizeVersion - adds the leading v release tags use, so 1.21.0 and v1.21.0
+// refer to the same download and the same installed file
+func NormalizeVersion(version string) string {
+	if releaseTag.MatchString(version) && !strings.HasPrefix(version, "v") {
+		return "v" + version
+	}
+
+	return version
+}
+
+func (m *Manager) cachedResolution(version string) (string, bool) {
+	m.resolvedLock.Lock()
+	defer m.resolvedLock.Unlock()
+
+	resolved, ok := m.resolved[version]
+	return resolved, ok
+}
+
+func (m *Manager) cacheResolution(version, resolved string) {
+	m.resolvedLock.Lock()
+	defer m.resolvedLock.Unlock()
+
+	m.resolved[version] = resolved
+}
@@ -0,0 +1,105 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import "testing"
+
+func TestValidateVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		valid   bool
+	}{
+		{"v1.21.0", true},
+		{"1.21.0", true},
+		{"v1.22.0-rc.1", true},
+		{"v1.22.0-alpha.0", true},
+		{"v1.22.0-beta.2", true},
+		{"v0.0.1", true},
+		{"", false},
+		{"v1.21", false},
+		{"1", false},
+		{"vv1.21.0", false},
+		{"v1.21.0.1", false},
+		{"v01.21.0", false},
+		{"v1.21.0-", false},
+		{"v1.21.0 ", false},
+		{"../v1.21.0", false},
+		{"latest", false},
+		{"<html>", false},
+	}
+
+	for _, test := range tests {
+		err := ValidateVersion(test.version)
+		if test.valid && err != nil {
+			t.Errorf("%q: got %v, want it accepted", test.version, err)
+		}
+		if !test.valid && err == nil {
+			t.Errorf("%q: got no error, want it rejected", test.version)
+		}
+	}
+}
+
+func TestNormalizeVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{"1.21.0", "v1.21.0"},
+		{"v1.21.0", "v1.21.0"},
+		{"1.22.0-rc.1", "v1.22.0-rc.1"},
+		{"v1.22.0-rc.1", "v1.22.0-rc.1"},
+		{"", ""},
+		{"latest", "latest"},
+		{"latest-1.21", "latest-1.21"},
+		{"~1.21", "~1.21"},
+		{"1.21", "1.21"},
+		{"vv1.21.0", "vv1.21.0"},
+	}
+
+	for _, test := range tests {
+		if got := NormalizeVersion(test.version); got != test.want {
+			t.Errorf("%q: got %q, want %q", test.version, got, test.want)
+		}
+	}
+}
+
+func TestNormalizedVersionsShareAPath(t *testing.T) {
+	if BinaryPath("dir", NormalizeVersion("1.21.0")) != BinaryPath("dir", NormalizeVersion("v1.21.0")) {
+		t.Error("1.21.0 and v1.21.0 map to different files")
+	}
+}
+
+func TestResolveUsesMirror(t *testing.T) {
+	server := releaseServer(map[string][]byte{
+		"/stable.txt":      []byte("v1.22.3\n"),
+		"/stable-1.21.txt": []byte("v1.21.7\n"),
+	})
+	defer server.Close()
+	m, cleanup := testManager(t, server)
+	defer cleanup()
+
+	for keyword, want := range map[string]string{"latest": "v1.22.3", "stable": "v1.22.3", "latest-1.21": "v1.21.7"} {
+		got, err := m.Resolve(keyword)
+		if err != nil {
+			t.Errorf("%s: %v", keyword, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: got %s, want %s", keyword, got, want)
+		}
+	}
+}
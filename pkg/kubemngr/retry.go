@@ -0,0 +1,72 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"time"
+
+	getter "github.com/hashicorp/go-getter"
+)
+
+// getWithRetry - runs client.Get up to attempts times, backing off exponentially
+// between transient failures. go-getter resumes from whatever an earlier attempt
+// left in client.Dst when the server supports range requests.
+func (m *Manager) getWithRetry(client *getter.Client, attempts int) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		m.debugf("Download attempt %d of %d for %s", attempt, attempts, client.Src)
+		err = client.Get()
+		if err == nil || attempt >= attempts {
+			return err
+		}
+
+		// Don't retry once the download has been cancelled or timed out
+		if client.Ctx != nil && client.Ctx.Err() != nil {
+			return err
+		}
+
+		if !m.isRetryable(client.Src) {
+			return err
+		}
+
+		backoff := time.Duration(1<<uint(attempt-1)) * time.Second
+		m.warnf("download failed: %v, retrying in %s", err, backoff)
+
+		select {
+		case <-client.Ctx.Done():
+			return client.Ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// isRetryable - decides whether a failed download of url is worth retrying.
+// go-getter flattens its errors into strings, so probe the URL instead: network
+// errors and 5xx responses are transient, 4xx responses won't fix themselves.
+func (m *Manager) isRetryable(url string) bool {
+	res, err := m.HTTPClient().Head(url)
+	if err != nil {
+		return true
+	}
+	res.Body.Close()
+
+	return res.StatusCode < 400 || res.StatusCode >= 500
+}
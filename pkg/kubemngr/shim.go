@@ -0,0 +1,77 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// shimMarker identifies kubectl shims written by WriteShim
+const shimMarker = "kubemngr shim"
+
+// ShimPath - returns where the kubectl shim is written, in the bin directory
+// next to the kubectl link it replaces
+func (m *Manager) ShimPath() string {
+	return filepath.Join(BinDir(m.opts.Home), "kubectl"+shimSuffix)
+}
+
+// WriteShim - replaces the kubectl link with a script that runs kubemngr,
+// the executable at exe, to pick the kubectl version for the working
+// directory every time it is called. Rewriting an existing shim is harmless.
+func (m *Manager) WriteShim(exe string) error {
+	shim := m.ShimPath()
+	if err := ensureDir(filepath.Dir(shim), m.opts.DirMode); err != nil {
+		return err
+	}
+
+	tmp := shim + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(shimScript(exe)), m.opts.BinaryMode&^m.umask); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, shim); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	// Windows looks for kubectl.exe before kubectl.cmd
+	if link := m.LinkPath(); link != shim {
+		if err := os.Remove(link); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// HasShim - reports whether the kubectl shim is in place
+func (m *Manager) HasShim() bool {
+	return isShim(m.ShimPath())
+}
+
+// isShim - reports whether the file at path is a shim written by WriteShim
+func isShim(path string) bool {
+	info, err := os.Lstat(path)
+	if err != nil || !info.Mode().IsRegular() || info.Size() > 4096 {
+		return false
+	}
+
+	data, err := ioutil.ReadFile(path)
+	return err == nil && bytes.Contains(data, []byte(shimMarker))
+}
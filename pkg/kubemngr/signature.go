@@ -0,0 +1,276 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"strings"
+)
+
+const (
+	// SigningIdentity is the identity Kubernetes releases are signed as
+	SigningIdentity = "krel-trust@k8s-releng-prod.iam.gserviceaccount.com"
+
+	// SigningIssuer is the OIDC issuer that vouches for SigningIdentity
+	SigningIssuer = "https://accounts.google.com"
+)
+
+var (
+	// oidIssuer and oidIssuerV2 are the Fulcio extensions holding the OIDC
+	// issuer of a keyless signing certificate, as a raw string and as DER
+	oidIssuer   = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 1}
+	oidIssuerV2 = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 57264, 1, 8}
+)
+
+// verifySignature - checks the cosign signature published next to url (url.sig)
+// against the binary at path, whose SHA256 digest has already been computed.
+// The trusted key in keyFile is either a PEM public key the release was signed
+// with, or PEM CA certificates that the signing certificate published at
+// url.cert must chain up to. A signing certificate must also have been issued
+// to SigningIdentity by SigningIssuer, anyone can get one from a public CA.
+func (m *Manager) verifySignature(path string, digest []byte, url, keyFile string) error {
+	if keyFile == "" {
+		return errors.New("no trusted key configured")
+	}
+
+	trusted, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return fmt.Errorf("unable to read trusted key: %w", err)
+	}
+
+	block, _ := pem.Decode(trusted)
+	if block == nil {
+		return fmt.Errorf("%s is not PEM encoded", keyFile)
+	}
+
+	signature, err := m.fetchBase64(url + ".sig")
+	if err != nil {
+		return fmt.Errorf("unable to fetch signature: %w", err)
+	}
+
+	var key crypto.PublicKey
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err = x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("unable to parse trusted key: %w", err)
+		}
+	case "CERTIFICATE":
+		key, err = m.signingKey(url+".cert", trusted)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unsupported trusted key type %q in %s", block.Type, keyFile)
+	}
+
+	if err := verifyDigest(key, digest, signature); err != nil {
+		return fmt.Errorf("signature verification failed for %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// signingKey - fetches the signing certificate at url, checks it chains up to
+// the trusted CAs for code signing and was issued to SigningIdentity, and
+// returns its public key. Certificates after the first at url, and those in
+// trusted that aren't self-signed, are used as intermediates.
+func (m *Manager) signingKey(url string, trusted []byte) (crypto.PublicKey, error) {
+	roots := x509.NewCertPool()
+	intermediates := x509.NewCertPool()
+
+	cas, err := parseCertificates(trusted)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse trusted certificate: %w", err)
+	}
+	for _, ca := range cas {
+		if ca.CheckSignatureFrom(ca) == nil {
+			roots.AddCert(ca)
+		} else {
+			intermediates.AddCert(ca)
+		}
+	}
+
+	raw, err := m.fetchBase64(url)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch signing certificate: %w", err)
+	}
+
+	chain, err := parseCertificates(raw)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse signing certificate at %s: %w", url, err)
+	}
+	cert := chain[0]
+	for _, intermediate := range chain[1:] {
+		intermediates.AddCert(intermediate)
+	}
+
+	// Keyless signing certificates are only valid for a few minutes around
+	// the time of signing, so verify the chain as of then.
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   cert.NotBefore,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageCodeSigning},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("signing certificate is not trusted: %w", err)
+	}
+
+	if err := checkSigner(cert, SigningIdentity, SigningIssuer); err != nil {
+		return nil, fmt.Errorf("signing certificate is not trusted: %w", err)
+	}
+
+	return cert.PublicKey, nil
+}
+
+// checkSigner - makes sure cert was issued to identity, as an email or URI
+// subject alternative name, after it signed in with issuer
+func checkSigner(cert *x509.Certificate, identity, issuer string) error {
+	found := false
+	for _, email := range cert.EmailAddresses {
+		found = found || email == identity
+	}
+	for _, uri := range cert.URIs {
+		found = found || uri.String() == identity
+	}
+	if !found {
+		return fmt.Errorf("it was issued to %s, not %s", certIdentities(cert), identity)
+	}
+
+	certIssuer, err := oidcIssuer(cert)
+	if err != nil {
+		return err
+	}
+	if certIssuer != issuer {
+		return fmt.Errorf("its identity was vouched for by %q, not %s", certIssuer, issuer)
+	}
+
+	return nil
+}
+
+// certIdentities - lists the email and URI names of cert for error messages
+func certIdentities(cert *x509.Certificate) string {
+	names := append([]string{}, cert.EmailAddresses...)
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	if len(names) == 0 {
+		return "no identity"
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// oidcIssuer - returns the OIDC issuer Fulcio recorded in cert
+func oidcIssuer(cert *x509.Certificate) (string, error) {
+	for _, ext := range cert.Extensions {
+		switch {
+		case ext.Id.Equal(oidIssuerV2):
+			var issuer string
+			if _, err := asn1.Unmarshal(ext.Value, &issuer); err != nil {
+				return "", fmt.Errorf("malformed OIDC issuer: %w", err)
+			}
+			return issuer, nil
+		case ext.Id.Equal(oidIssuer):
+			return string(ext.Value), nil
+		}
+	}
+
+	return "", errors.New("it doesn't record an OIDC issuer")
+}
+
+// parseCertificates - parses every PEM certificate in data, in order
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM certificate found")
+	}
+
+	return certs, nil
+}
+
+// verifyDigest - checks signature is a valid signature of the SHA256 digest by key
+func verifyDigest(key crypto.PublicKey, digest, signature []byte) error {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		var sig struct{ R, S *big.Int }
+		if _, err := asn1.Unmarshal(signature, &sig); err != nil {
+			return fmt.Errorf("malformed signature: %w", err)
+		}
+		if !ecdsa.Verify(k, digest, sig.R, sig.S) {
+			return errors.New("invalid signature")
+		}
+		return nil
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(k, crypto.SHA256, digest, signature)
+	case ed25519.PublicKey:
+		return errors.New("ed25519 keys sign the whole binary rather than its digest and are not supported")
+	}
+
+	return fmt.Errorf("unsupported key type %T", key)
+}
+
+// fetchBase64 - downloads a base64 encoded file, as cosign publishes signatures and certificates
+func (m *Manager) fetchBase64(url string) ([]byte, error) {
+	m.debugf("Fetching %s", url)
+	res, err := m.HTTPClient().Get(url)
+	if err != nil {
+		return nil, m.requestError("unable to fetch "+url, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned %s", url, res.Status)
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(string(body)))
+}
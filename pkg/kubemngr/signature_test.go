@@ -0,0 +1,151 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCA - returns a self-signed CA certificate and its key
+func testCA(t *testing.T) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test root"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, key
+}
+
+// testSigningCert - issues a keyless style signing certificate for identity
+// and issuer from ca, returning it PEM encoded with its key
+func testSigningCert(t *testing.T, ca *x509.Certificate, caKey *ecdsa.PrivateKey, identity, issuer string, usage x509.ExtKeyUsage) ([]byte, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuerExt, err := asn1.MarshalWithParams(issuer, "utf8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:    big.NewInt(2),
+		NotBefore:       time.Now().Add(-time.Minute),
+		NotAfter:        time.Now().Add(10 * time.Minute),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{usage},
+		EmailAddresses:  []string{identity},
+		ExtraExtensions: []pkix.Extension{{Id: oidIssuerV2, Value: issuerExt}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), key
+}
+
+func TestVerifySignatureIdentity(t *testing.T) {
+	ca, caKey := testCA(t)
+	binary := fakeELF
+	digest := sha256.Sum256(binary)
+
+	tests := []struct {
+		name     string
+		identity string
+		issuer   string
+		usage    x509.ExtKeyUsage
+		err      string
+	}{
+		{"release signer", SigningIdentity, SigningIssuer, x509.ExtKeyUsageCodeSigning, ""},
+		{"other identity", "mallory@example.com", SigningIssuer, x509.ExtKeyUsageCodeSigning, "not " + SigningIdentity},
+		{"other issuer", SigningIdentity, "https://example.com", x509.ExtKeyUsageCodeSigning, "not " + SigningIssuer},
+		{"not for code signing", SigningIdentity, SigningIssuer, x509.ExtKeyUsageServerAuth, "not trusted"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cert, key := testSigningCert(t, ca, caKey, test.identity, test.issuer, test.usage)
+			r, sig, err := ecdsa.Sign(rand.Reader, key, digest[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			signature, err := asn1.Marshal(struct{ R, S *big.Int }{r, sig})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			server := releaseServer(map[string][]byte{
+				"/kubectl.sig":  []byte(base64.StdEncoding.EncodeToString(signature)),
+				"/kubectl.cert": []byte(base64.StdEncoding.EncodeToString(cert)),
+			})
+			defer server.Close()
+			m, cleanup := testManager(t, server)
+			defer cleanup()
+
+			keyFile := filepath.Join(filepath.Dir(m.opts.Home), "root.pem")
+			if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw}), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			err = m.verifySignature("kubectl", digest[:], server.URL+"/kubectl", keyFile)
+			switch {
+			case test.err == "" && err != nil:
+				t.Errorf("got %v, want the signature accepted", err)
+			case test.err != "" && (err == nil || !strings.Contains(err.Error(), test.err)):
+				t.Errorf("got %v, want an error saying %q", err, test.err)
+			}
+		})
+	}
+}
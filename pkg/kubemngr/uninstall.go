@@ -0,0 +1,70 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Uninstall - removes an installed version and its entry in the manifest
+func (m *Manager) Uninstall(version string) error {
+	path, err := m.Path(version)
+	if err != nil {
+		return err
+	}
+
+	return m.remove(version, path)
+}
+
+// UninstallBinary - removes an installed version of binary, such as kubeadm
+func (m *Manager) UninstallBinary(binary, version string) error {
+	path, err := m.PathFor(binary, version)
+	if err != nil {
+		return err
+	}
+
+	return m.remove(version, path)
+}
+
+// UninstallPlatform - removes the build of version for sys and machine, which
+// default to this machine's OS and arch when empty
+func (m *Manager) UninstallPlatform(version, sys, machine string) error {
+	path, err := m.PlatformPath(version, sys, machine)
+	if err != nil {
+		return err
+	}
+
+	return m.remove(version, path)
+}
+
+// remove - deletes the binary at path and drops it from the manifest
+func (m *Manager) remove(version, path string) error {
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+
+	err := m.updateManifest(func(manifest Manifest) {
+		delete(manifest, filepath.Base(path))
+	})
+	if err != nil {
+		return fmt.Errorf("removed %s but unable to update %s: %w", filepath.Base(path), ManifestFile, err)
+	}
+
+	return nil
+}
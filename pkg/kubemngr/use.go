@@ -0,0 +1,193 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// linkTargetSuffix is appended to a link that had to be copied to name the
+// file recording what it was copied from
+const linkTargetSuffix = ".target"
+
+// Use - points the kubectl symlink at an installed version, replacing the shim
+func (m *Manager) Use(version string) error {
+	kubectl, err := m.Path(version)
+	if err != nil {
+		return err
+	}
+
+	link := m.LinkPath()
+	if err := ensureDir(filepath.Dir(link), m.opts.DirMode); err != nil {
+		return err
+	}
+
+	if err := replaceLink(kubectl, link); err != nil {
+		return err
+	}
+
+	// Windows keeps the shim next to kubectl.exe, which would shadow it anyway
+	if shim := m.ShimPath(); shim != link && isShim(shim) {
+		os.Remove(shim)
+	}
+
+	if err := m.linkConvert(version); err != nil {
+		return err
+	}
+
+	return m.forwardLegacyLink(link)
+}
+
+// UseBinary - points the symlink for binary, such as kubeadm, in the bin
+// directory at an installed version of it
+func (m *Manager) UseBinary(binary, version string) error {
+	if binary == DefaultBinary {
+		return m.Use(version)
+	}
+
+	path, err := m.PathFor(binary, version)
+	if err != nil {
+		return err
+	}
+
+	link := m.LinkPathFor(binary)
+	if err := ensureDir(filepath.Dir(link), m.opts.DirMode); err != nil {
+		return err
+	}
+
+	return replaceLink(path, link)
+}
+
+// linkConvert - points the kubectl-convert link at the plugin built for the
+// same version as kubectl, so kubectl convert keeps matching kubectl. A link to
+// a plugin of another version is removed when this version has none.
+func (m *Manager) linkConvert(version string) error {
+	link := m.LinkPathFor(ConvertBinary)
+
+	plugin := BinaryPathFor(m.opts.Home, ConvertBinary, NormalizeVersion(version))
+	if _, err := os.Stat(plugin); err == nil {
+		return replaceLink(plugin, link)
+	}
+
+	if target, err := readLink(link); err == nil && strings.HasPrefix(target, m.opts.Home+string(filepath.Separator)) {
+		return os.Remove(link)
+	}
+
+	return nil
+}
+
+// replaceLink - creates the new link next to the old one and renames it into
+// place so a failed switch never leaves kubectl missing or dangling
+func replaceLink(target, link string) error {
+	tmpLink := link + ".tmp"
+	os.Remove(tmpLink)
+
+	if err := linkKubectl(target, tmpLink); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpLink, link); err != nil {
+		os.Remove(tmpLink)
+		return err
+	}
+
+	// Windows falls back to a copy, which can't be read back to find the version
+	info, err := os.Lstat(link)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 {
+		os.Remove(link + linkTargetSuffix)
+		return nil
+	}
+
+	return ioutil.WriteFile(link+linkTargetSuffix, []byte(target), 0644)
+}
+
+// readLink - returns the target of link, reading the file replaceLink records
+// next to it when link is a copy rather than a symlink
+func readLink(link string) (string, error) {
+	target, err := os.Readlink(link)
+	if err == nil || os.IsNotExist(err) {
+		return target, err
+	}
+
+	recorded, readErr := ioutil.ReadFile(link + linkTargetSuffix)
+	if readErr != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(recorded)), nil
+}
+
+// forwardLegacyLink - keeps the ~/.local/bin/kubectl symlink earlier releases
+// created working by pointing it at link. Anything else at that path is left alone.
+func (m *Manager) forwardLegacyLink(link string) error {
+	legacy, err := LegacyLinkPath()
+	if err != nil || legacy == link {
+		return nil
+	}
+
+	target, err := os.Readlink(legacy)
+	if err != nil || target == link {
+		return nil
+	}
+	if !strings.HasPrefix(target, m.opts.Home+string(filepath.Separator)) {
+		return nil
+	}
+
+	return replaceLink(link, legacy)
+}
+
+// Active - returns the version the kubectl symlink currently points to, falling
+// back to the symlink of earlier releases until use has been run
+func (m *Manager) Active() (string, error) {
+	target, err := readLink(m.LinkPath())
+	if os.IsNotExist(err) {
+		var legacy string
+		if legacy, err = LegacyLinkPath(); err == nil {
+			target, err = os.Readlink(legacy)
+		}
+		// use points the legacy link at the link in another kubemngr directory
+		if err == nil && filepath.Base(target) == DefaultBinary+ExeSuffix {
+			target, err = os.Readlink(target)
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return VersionFromBinaryName(filepath.Base(target)), nil
+}
+
+// ActiveBinary - returns the version the symlink for binary, such as kubeadm,
+// currently points to
+func (m *Manager) ActiveBinary(binary string) (string, error) {
+	if binary == DefaultBinary {
+		return m.Active()
+	}
+
+	target, err := readLink(m.LinkPathFor(binary))
+	if err != nil {
+		return "", err
+	}
+
+	return VersionFromBinaryName(filepath.Base(target)), nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestActiveFromCopiedLink(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "kubemngr-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	m, err := New(Options{Home: filepath.Join(tmp, "kubemngr")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ensureDir(m.opts.Home, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range []string{"v1.21.0", "v1.22.0"} {
+		if err := ioutil.WriteFile(BinaryPath(m.opts.Home, version), fakeELF, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Windows copies kubectl when it can't symlink, replaceLink records the target
+	if err := m.Use("v1.21.0"); err != nil {
+		t.Fatal(err)
+	}
+	link := m.LinkPath()
+	if err := os.Remove(link); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := copyFile(BinaryPath(m.opts.Home, "v1.21.0"), link); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(link+linkTargetSuffix, []byte(BinaryPath(m.opts.Home, "v1.21.0")), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if active, err := m.Active(); err != nil || active != "v1.21.0" {
+		t.Errorf("got %q, %v, want v1.21.0", active, err)
+	}
+
+	// A symlink needs no record, a stale one would be misleading
+	if err := m.Use("v1.22.0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(link + linkTargetSuffix); !os.IsNotExist(err) {
+		t.Errorf("the record of the copied link was left behind: %v", err)
+	}
+	if active, err := m.Active(); err != nil || active != "v1.22.0" {
+		t.Errorf("got %q, %v, want v1.22.0", active, err)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// execCheckTimeout bounds how long a freshly downloaded kubectl gets to print its version
+const execCheckTimeout = 10 * time.Second
+
+var (
+	elfMagic = []byte{0x7f, 'E', 'L', 'F'}
+	peMagic  = []byte{'M', 'Z'}
+
+	machoMagics = [][]byte{
+		{0xfe, 0xed, 0xfa, 0xce}, // 32-bit big endian
+		{0xce, 0xfa, 0xed, 0xfe}, // 32-bit little endian
+		{0xfe, 0xed, 0xfa, 0xcf}, // 64-bit big endian
+		{0xcf, 0xfa, 0xed, 0xfe}, // 64-bit little endian
+		{0xca, 0xfe, 0xba, 0xbe}, // universal binary
+	}
+)
+
+// validateExecutable - checks the magic bytes of a file match an executable for the given OS
+func validateExecutable(path, goos string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(f, header); err != nil {
+		return fmt.Errorf("%s is too small to be a kubectl binary", path)
+	}
+
+	if !isExecutable(header, goos) {
+		return fmt.Errorf("%s is not a valid %s executable", path, goos)
+	}
+
+	return nil
+}
+
+// isExecutable - reports whether header starts with the executable magic for goos
+func isExecutable(header []byte, goos string) bool {
+	switch goos {
+	case "linux":
+		return bytes.HasPrefix(header, elfMagic)
+	case "darwin":
+		for _, magic := range machoMagics {
+			if bytes.HasPrefix(header, magic) {
+				return true
+			}
+		}
+	case "windows":
+		return bytes.HasPrefix(header, peMagic)
+	}
+
+	return false
+}
+
+// versionArgs are the arguments that make each binary print its version and
+// exit. kubectl-convert has none, so it isn't run.
+var versionArgs = map[string][]string{
+	"kubectl": {"version", "--client"},
+	"kubeadm": {"version", "-o", "short"},
+	"kubelet": {"--version"},
+}
+
+// verifyRuns - runs kubectl version --client, or the equivalent for binary,
+// with path to make sure it actually executes on this machine, returning the
+// first line it printed
+func verifyRuns(ctx context.Context, binary, path string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, execCheckTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, path, versionArgs[binary]...).CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", fmt.Errorf("%s did not finish within %s", path, execCheckTimeout)
+	}
+
+	firstLine := strings.TrimSpace(strings.SplitN(string(out), "\n", 2)[0])
+	if err != nil {
+		return "", fmt.Errorf("%s does not run on this machine: %v: %s", path, err, firstLine)
+	}
+
+	return firstLine, nil
+}
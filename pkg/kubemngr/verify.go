@@ -0,0 +1,121 @@
+/*
+Copyright © 2019 Zee Ahmed <zee@simplyzee.dev>
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubemngr
+
+import (
+	"path/filepath"
+)
+
+// Verification results reported by Verify
+const (
+	VerifyOK      = "ok"
+	VerifyCorrupt = "corrupt"
+	VerifyUnknown = "unknown"
+)
+
+// VerifyResult is the outcome of checking one installed binary
+type VerifyResult struct {
+	Version string
+	Path    string
+
+	// Status is VerifyOK, VerifyCorrupt or VerifyUnknown when there is no
+	// checksum to compare against
+	Status string
+
+	Expected string
+	Actual   string
+}
+
+// Verify - recomputes the SHA256 of version, or of every installed version
+// when empty, and compares it with the checksum recorded in the manifest at
+// install time. With fetch the published checksum is used for binaries the
+// manifest has no record of, they stay unknown when it can't be fetched.
+func (m *Manager) Verify(version string, fetch bool) ([]VerifyResult, error) {
+	var paths []string
+	if version != "" {
+		path, err := m.Path(version)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	} else {
+		installed, err := m.List()
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range installed {
+			paths = append(paths, v.Path)
+		}
+	}
+
+	// The recorded checksums are what's being checked, so don't let a rebuild
+	// fill in missing ones from the binaries themselves
+	manifest := m.readManifest()
+
+	var results []VerifyResult
+	for _, path := range paths {
+		name := filepath.Base(path)
+		result := VerifyResult{
+			Version:  NormalizeVersion(VersionFromBinaryName(name)),
+			Path:     path,
+			Expected: manifest[name].SHA256,
+		}
+
+		if result.Expected == "" && fetch {
+			expected, err := m.publishedChecksum(result.Version)
+			if err != nil {
+				m.warnf("%v", err)
+			}
+			result.Expected = expected
+		}
+
+		actual, err := fileChecksum(path)
+		if err != nil {
+			return nil, err
+		}
+		result.Actual = actual
+
+		switch {
+		case result.Expected == "":
+			result.Status = VerifyUnknown
+		case result.Expected == actual:
+			result.Status = VerifyOK
+		default:
+			result.Status = VerifyCorrupt
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// publishedChecksum - fetches the checksum published for the build of version
+// for this machine
+func (m *Manager) publishedChecksum(version string) (string, error) {
+	base, err := m.BaseURL()
+	if err != nil {
+		return "", err
+	}
+
+	sys, machine, err := hostPlatform()
+	if err != nil {
+		return "", err
+	}
+
+	return m.fetchChecksum(binaryURL(base, DefaultBinary, version, sys, machine) + ".sha256")
+}